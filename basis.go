@@ -0,0 +1,307 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #cgo CFLAGS: -I/usr/include/lpsolve/
+// #cgo LDFLAGS: -llpsolve55 -lm -ldl -lcolamd
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+	"math"
+	"unsafe"
+)
+
+// Basis is a starting point for the simplex algorithm, as obtained from a
+// previous solve with SolveResult.Basis. Starting from the basis of a
+// similar model can considerably speed up solving.
+//
+// A basis is only valid for models with the same number of variables and
+// constraints as the one it was obtained from.
+type Basis struct {
+	rows, cols int
+	// indices is the basis as used by the underlying library: element 0 is
+	// unused, followed by the basic and then the non-basic variables
+	indices []C.int
+}
+
+// Basis returns the final basis of this optimization result, to be used
+// as starting basis with Model.SetBasis.
+// The basis of presolved results is not available.
+func (res SolveResult) Basis() (*Basis, error) {
+	if res.sol.presolved {
+		return nil, fmt.Errorf("basis of presolved results not available")
+	}
+
+	prob, unlock, err := res.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return getBasis(prob)
+}
+
+// WriteBasis writes the final basis of this optimization result to the
+// given file, in the MPS basis format.
+// The basis of presolved results is not available.
+func (res SolveResult) WriteBasis(filename string) error {
+	if res.sol.presolved {
+		return fmt.Errorf("basis of presolved results not available")
+	}
+
+	prob, unlock, err := res.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	c_filename := C.CString(filename)
+	defer C.free(unsafe.Pointer(c_filename))
+
+	if C.write_basis(prob, c_filename) != C.TRUE {
+		return fmt.Errorf("could not write basis to %s", filename)
+	}
+
+	return nil
+}
+
+// SetBasis sets the starting basis for subsequent solves of the model.
+// Solves with presolve enabled do not use the basis, and neither do solves
+// after variables or constraints were added or removed, which start from
+// the default basis instead.
+func (model *Model) SetBasis(b *Basis) error {
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	if err := model.checkOpen(); err != nil {
+		return err
+	}
+
+	if err := b.check(model.prob); err != nil {
+		return err
+	}
+
+	model.basis = b
+	model.start = nil
+
+	return nil
+}
+
+// SetStartingSolution sets the starting point for subsequent solves of the
+// model to the given (possibly partial) solution. Variables not included
+// are assumed to be 0.
+//
+// The solution is used in two ways: the simplex algorithm starts from a
+// basis derived from it, and, if the solution is feasible for the model at
+// the time of solving, including integrality, its objective value serves
+// as a cutoff for the branch-and-bound search of models with integer
+// variables, so that only better solutions are searched for. Solutions
+// with non-zero values for variables of special ordered sets are only used
+// for the basis.
+func (model *Model) SetStartingSolution(values map[*Variable]float64) error {
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	if err := model.checkOpen(); err != nil {
+		return err
+	}
+
+	// both vectors are 1-indexed
+	guess := make([]C.REAL, len(model.vars)+1)
+	start := make(map[*Variable]float64, len(values))
+	for v, value := range values {
+		if v.model != model || v.index < 0 {
+			return fmt.Errorf("variable not part of model")
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return fmt.Errorf("starting value of %s is not finite", C.GoString(C.get_col_name(model.prob, C.int(v.index+1))))
+		}
+		guess[v.index+1] = C.REAL(value)
+		start[v] = value
+	}
+
+	b := &Basis{
+		rows:    len(model.constraints),
+		cols:    len(model.vars),
+		indices: make([]C.int, len(model.constraints)+len(model.vars)+1),
+	}
+
+	if C.guess_basis(model.prob, &guess[0], &b.indices[0]) != C.TRUE {
+		return fmt.Errorf("could not derive basis from solution")
+	}
+
+	model.basis = b
+	model.start = start
+
+	return nil
+}
+
+// ReadBasis sets the starting basis for subsequent solves of the model
+// from the given file, in the MPS basis format.
+func (model *Model) ReadBasis(filename string) error {
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	if err := model.checkOpen(); err != nil {
+		return err
+	}
+
+	c_filename := C.CString(filename)
+	defer C.free(unsafe.Pointer(c_filename))
+
+	if C.read_basis(model.prob, c_filename, nil) != C.TRUE {
+		return fmt.Errorf("could not read basis from %s", filename)
+	}
+
+	b, err := getBasis(model.prob)
+	if err != nil {
+		return err
+	}
+
+	model.basis = b
+	model.start = nil
+
+	return nil
+}
+
+// ResetBasis makes subsequent solves of the model start from the default
+// basis again, discarding any starting solution.
+func (model *Model) ResetBasis() {
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	model.basis = nil
+	model.start = nil
+}
+
+func getBasis(prob *C.lprec) (*Basis, error) {
+	b := &Basis{
+		rows: int(C.get_Nrows(prob)),
+		cols: int(C.get_Ncolumns(prob)),
+	}
+	b.indices = make([]C.int, b.rows+b.cols+1)
+
+	if C.get_basis(prob, &b.indices[0], C.TRUE) != C.TRUE {
+		return nil, fmt.Errorf("could not get basis")
+	}
+
+	return b, nil
+}
+
+// check returns an error if the basis does not fit the given problem.
+func (b *Basis) check(prob *C.lprec) error {
+	rows, cols := int(C.get_Nrows(prob)), int(C.get_Ncolumns(prob))
+	if b.rows != rows || b.cols != cols {
+		return fmt.Errorf("basis for %d constraints and %d variables does not fit model with %d and %d", b.rows, b.cols, rows, cols)
+	}
+
+	return nil
+}
+
+// apply sets the basis on the given problem.
+func (b *Basis) apply(prob *C.lprec) error {
+	if err := b.check(prob); err != nil {
+		return err
+	}
+
+	// set_basis does not change the given basis, but is not declared const
+	indices := append([]C.int(nil), b.indices...)
+	if C.set_basis(prob, &indices[0], C.TRUE) != C.TRUE {
+		return fmt.Errorf("invalid basis")
+	}
+
+	return nil
+}
+
+// applyStart sets the objective value of the model's starting solution as
+// branch-and-bound cutoff on the given copy of the model's problem, if the
+// solution is feasible. The model's lock must be held.
+func (model *Model) applyStart(prob *C.lprec) {
+	values := make([]float64, len(model.vars))
+	for v, value := range model.start {
+		// variables may have been removed since
+		if v.index >= 0 {
+			values[v.index] = value
+		}
+	}
+
+	objective, ok := startObjective(prob, values)
+	if !ok {
+		return
+	}
+
+	// the cutoff is slightly worse than the starting solution, which must
+	// not be pruned by the improvement thresholds of the search
+	margin := float64(C.get_mip_gap(prob, C.TRUE)) +
+		(float64(C.get_mip_gap(prob, C.FALSE))+1e-6)*(1+math.Abs(objective))
+	if C.is_maxim(prob) == C.TRUE {
+		margin = -margin
+	}
+
+	C.set_obj_bound(prob, C.REAL(objective+margin))
+}
+
+// startObjective returns the objective value of the given solution,
+// indexed by column, and whether the solution is feasible for prob.
+// Solutions with non-zero values for variables of special ordered sets are
+// reported as infeasible, since these sets cannot be checked.
+func startObjective(prob *C.lprec, values []float64) (float64, bool) {
+	nrows := int(C.get_Nrows(prob))
+	epsPrimal := float64(C.get_epsprimal(prob))
+	epsInt := float64(C.get_epsint(prob))
+
+	// row 0 is the objective function
+	activity := make([]float64, nrows+1)
+	column := make([]C.REAL, nrows+1)
+	nzrow := make([]C.int, nrows+1)
+
+	for i, value := range values {
+		col := C.int(i + 1)
+
+		if value != 0 && C.is_SOS_var(prob, col) == C.TRUE {
+			return 0, false
+		}
+		if C.is_int(prob, col) == C.TRUE && math.Abs(value-math.Round(value)) > epsInt {
+			return 0, false
+		}
+		// semi-continuous variables may also be 0
+		lower, upper := colBounds(prob, i+1)
+		if !(value == 0 && C.is_semicont(prob, col) == C.TRUE) && (value < lower-epsPrimal || value > upper+epsPrimal) {
+			return 0, false
+		}
+
+		n := int(C.get_columnex(prob, col, &column[0], &nzrow[0]))
+		for k := 0; k < n; k++ {
+			activity[nzrow[k]] += float64(column[k]) * value
+		}
+	}
+
+	for i := 1; i <= nrows; i++ {
+		lower, upper := rowBounds(prob, i)
+		tolerance := epsPrimal * (1 + math.Abs(activity[i]))
+		if activity[i] < lower-tolerance || activity[i] > upper+tolerance {
+			return 0, false
+		}
+	}
+
+	// the right-hand side of the objective function is its constant
+	return activity[0] + float64(C.get_rh(prob, 0)), true
+}
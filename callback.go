@@ -0,0 +1,110 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #cgo CFLAGS: -I/usr/include/lpsolve/
+// #cgo LDFLAGS: -llpsolve55 -lm -ldl -lcolamd
+// #include <lp_lib.h>
+// #include <stdlib.h>
+/*
+// https://golang.org/issue/19837
+extern void msgCallback(lprec *lp, void *userhandle, int msg);
+*/
+import "C"
+
+import (
+	"math"
+	"unsafe"
+)
+
+// ImprovedSolution describes an integer solution found during the
+// branch-and-bound search that is better than all previously found ones.
+type ImprovedSolution struct {
+	// Objective is the objective value of the solution.
+	Objective float64
+	// RootBound is the objective value of the root LP relaxation of the
+	// model, which no integer solution can improve upon. Since it is not
+	// tightened during branch-and-bound, it is not the current bound of
+	// the search.
+	RootBound float64
+
+	vars   map[*Variable]int
+	values []float64
+}
+
+// Value returns the value of the given variable in this solution.
+// Variables added to the model after solving started have a value of NaN.
+func (s ImprovedSolution) Value(v *Variable) float64 {
+	index, ok := s.vars[v]
+	if !ok {
+		return math.NaN()
+	}
+
+	return s.values[index]
+}
+
+// improvementWatcher counts the improved solutions of a single solve and
+// delivers them to the model's callback, if any.
+type improvementWatcher struct {
+	callback func(ImprovedSolution)
+	vars     map[*Variable]int
+	count    int
+}
+
+// watchImprovements registers an improvementWatcher on the given snapshot
+// and returns it, along with the function to unregister it.
+func (model *Model) watchImprovements(sol *solution) (*improvementWatcher, func()) {
+	prob := sol.prob
+	w := &improvementWatcher{callback: model.improvedSolutionCallback, vars: sol.vars}
+
+	ref := saveRef(w)
+	C.put_msgfunc(prob, (*C.lphandleint_func)(C.msgCallback), ref, C.MSG_MILPFEASIBLE|C.MSG_MILPBETTER)
+
+	return w, func() {
+		C.put_msgfunc(prob, nil, nil, 0)
+		releaseRef(ref)
+	}
+}
+
+//export msgCallback
+func msgCallback(prob *C.lprec, watcherPtr unsafe.Pointer, msg C.int) {
+	w, ok := loadRef(watcherPtr).(*improvementWatcher)
+	if !ok {
+		return
+	}
+
+	w.count++
+	if w.callback == nil {
+		return
+	}
+
+	// like in SolveResult.PrimalValue, the variables follow the rows of
+	// the original problem
+	norigRows := int(C.get_Norig_rows(prob))
+	values := make([]float64, int(C.get_Norig_columns(prob)))
+	for i := range values {
+		values[i] = float64(C.get_var_primalresult(prob, C.int(norigRows+i+1)))
+	}
+
+	w.callback(ImprovedSolution{
+		Objective: float64(C.get_working_objective(prob)),
+		RootBound: rootBound(prob),
+		vars:      w.vars,
+		values:    values,
+	})
+}
@@ -0,0 +1,248 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #cgo CFLAGS: -I/usr/include/lpsolve/
+// #cgo LDFLAGS: -llpsolve55 -lm -ldl -lcolamd
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+	"math"
+	"unsafe"
+)
+
+type Constraint struct {
+	model *Model
+	index int
+}
+
+type ConstraintType int
+
+const (
+	// LessOrEqualConstraint has only an upper bound.
+	LessOrEqualConstraint ConstraintType = iota
+	// GreaterOrEqualConstraint has only a lower bound.
+	GreaterOrEqualConstraint
+	// EqualConstraint has identical lower and upper bounds.
+	EqualConstraint
+	// RangeConstraint has distinct, finite lower and upper bounds.
+	RangeConstraint
+	// FreeConstraint has neither lower nor upper bound.
+	FreeConstraint
+)
+
+/* constraint-related functions (model rows) */
+
+// Name returns the name of a constraint
+func (c *Constraint) Name() string {
+	c.model.mu.RLock()
+	defer c.model.mu.RUnlock()
+
+	c.model.mustBeOpen()
+	c.mustBePresent()
+
+	return C.GoString(C.get_row_name(c.model.prob, C.int(c.index+1)))
+}
+
+// SetName changes the name of a constraint
+func (c *Constraint) SetName(name string) {
+	c.model.mu.Lock()
+	defer c.model.mu.Unlock()
+
+	c.model.mustBeOpen()
+	c.mustBePresent()
+
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	C.set_row_name(c.model.prob, C.int(c.index+1), c_name)
+}
+
+// SetBounds sets the lower and upper bounds of the constraint's
+// expression.
+// To leave one side of the constraint open, pass math.Inf(-1) or
+// math.Inf(1), respectively.
+func (c *Constraint) SetBounds(lower, upper float64) error {
+	if err := checkBounds(lower, upper); err != nil {
+		return err
+	}
+
+	c.model.mu.Lock()
+	defer c.model.mu.Unlock()
+
+	if err := c.model.checkOpen(); err != nil {
+		return err
+	}
+	if err := c.checkPresent(); err != nil {
+		return err
+	}
+
+	setRowBounds(c.model.prob, c.index+1, lower, upper)
+
+	return nil
+}
+
+// setRowBounds sets the bounds of the given row, using a single range
+// row when both bounds are finite and distinct.
+func setRowBounds(prob *C.lprec, rownr int, lower, upper float64) {
+	row := C.int(rownr)
+
+	// the constraint type must be set first, since it resets the range
+	// and might flip the row's sign
+	switch {
+	case math.IsInf(lower, 0) && math.IsInf(upper, 0):
+		C.set_constr_type(prob, row, C.LE)
+		C.set_rh(prob, row, C.get_infinite(prob))
+	case math.IsInf(lower, 0):
+		C.set_constr_type(prob, row, C.LE)
+		C.set_rh(prob, row, C.REAL(upper))
+	case math.IsInf(upper, 0):
+		C.set_constr_type(prob, row, C.GE)
+		C.set_rh(prob, row, C.REAL(lower))
+	case lower == upper:
+		C.set_constr_type(prob, row, C.EQ)
+		C.set_rh(prob, row, C.REAL(upper))
+	default:
+		C.set_constr_type(prob, row, C.LE)
+		C.set_rh(prob, row, C.REAL(upper))
+		C.set_rh_range(prob, row, C.REAL(upper-lower))
+	}
+}
+
+// Bounds returns the bounds currently set for this constraint.
+func (c *Constraint) Bounds() (lower, upper float64) {
+	c.model.mu.RLock()
+	defer c.model.mu.RUnlock()
+
+	c.model.mustBeOpen()
+	c.mustBePresent()
+
+	return rowBounds(c.model.prob, c.index+1)
+}
+
+// rowBounds returns the bounds of the given row, undoing setRowBounds.
+func rowBounds(prob *C.lprec, rownr int) (lower, upper float64) {
+	row := C.int(rownr)
+
+	inf := float64(C.get_infinite(prob))
+	rh := float64(C.get_rh(prob, row))
+	// the range is given as the distance between both bounds
+	rng := math.Abs(float64(C.get_rh_range(prob, row)))
+
+	switch C.get_constr_type(prob, row) {
+	case C.EQ:
+		return rh, rh
+	case C.GE:
+		lower, upper = rh, math.Inf(1)
+		if rng < inf {
+			upper = rh + rng
+		}
+	default:
+		lower, upper = math.Inf(-1), rh
+		if rng < inf {
+			lower = rh - rng
+		}
+	}
+
+	if lower <= -inf {
+		lower = math.Inf(-1)
+	}
+	if upper >= inf {
+		upper = math.Inf(1)
+	}
+	return
+}
+
+// Type returns the constraint's type, as given by its bounds.
+func (c *Constraint) Type() ConstraintType {
+	lower, upper := c.Bounds()
+
+	switch {
+	case math.IsInf(lower, 0) && math.IsInf(upper, 0):
+		return FreeConstraint
+	case math.IsInf(lower, 0):
+		return LessOrEqualConstraint
+	case math.IsInf(upper, 0):
+		return GreaterOrEqualConstraint
+	case lower == upper:
+		return EqualConstraint
+	default:
+		return RangeConstraint
+	}
+}
+
+// SetCoefficient sets the coefficient of the given variable in this
+// constraint.
+func (c *Constraint) SetCoefficient(v *Variable, coef float64) error {
+	c.model.mu.Lock()
+	defer c.model.mu.Unlock()
+
+	if err := c.model.checkOpen(); err != nil {
+		return err
+	}
+	if err := c.checkPresent(); err != nil {
+		return err
+	}
+
+	if v.model != c.model || v.index < 0 {
+		return fmt.Errorf("variable not part of model")
+	}
+
+	if C.set_mat(c.model.prob, C.int(c.index+1), C.int(v.index+1), C.REAL(coef)) != C.TRUE {
+		return fmt.Errorf("could not set coefficient")
+	}
+
+	return nil
+}
+
+// Coefficient returns the coefficient of the given variable in this
+// constraint. Variables not part of the model have a coefficient of 0.
+func (c *Constraint) Coefficient(v *Variable) float64 {
+	c.model.mu.RLock()
+	defer c.model.mu.RUnlock()
+
+	c.model.mustBeOpen()
+	c.mustBePresent()
+
+	if v.model != c.model || v.index < 0 {
+		return 0
+	}
+
+	return float64(C.get_mat(c.model.prob, C.int(c.index+1), C.int(v.index+1)))
+}
+
+// checkPresent returns an error if the constraint has been removed from its
+// model. The model's lock must be held.
+func (c *Constraint) checkPresent() error {
+	if c.index < 0 || c.index >= len(c.model.constraints) || c.model.constraints[c.index] != c {
+		return fmt.Errorf("constraint not part of model")
+	}
+
+	return nil
+}
+
+// mustBePresent is like checkPresent, but panics, for methods that cannot
+// return errors.
+func (c *Constraint) mustBePresent() {
+	if err := c.checkPresent(); err != nil {
+		panic(err)
+	}
+}
@@ -1,38 +1,50 @@
 package golpa
 
+// #include <stdlib.h>
+import "C"
+
 import (
-	"C"
-	"sync"
+	"runtime/cgo"
+	"sync/atomic"
 	"unsafe"
 )
 
 /*
- This code is used to work around the garbage collector and keep track of objects passed to callback code.
- Inspired by github.com/mattn/go-pointer
+ This code is used to pass Go values to callback code, which can only receive C pointers.
+ The values are kept alive by a cgo.Handle, which is stored in C memory. Every reference must be released once the C
+ code no longer uses it, otherwise both the handle and the referenced value leak.
 */
 
-var (
-	refsMu sync.Mutex
-	refs   = make(map[unsafe.Pointer]interface{})
-)
+// liveRefs counts the references that have not been released yet.
+var liveRefs atomic.Int64
 
 func saveRef(ref interface{}) unsafe.Pointer {
-	refsMu.Lock()
-	defer refsMu.Unlock()
-
-	var p unsafe.Pointer = C.malloc(C.size_t(1))
+	p := C.malloc(C.size_t(unsafe.Sizeof(cgo.Handle(0))))
 	if p == nil {
 		panic("could not allocate memory for CGO pointer tracking")
 	}
 
-	refs[p] = ref
+	*(*cgo.Handle)(p) = cgo.NewHandle(ref)
+	liveRefs.Add(1)
 
 	return p
 }
 
 func loadRef(ptr unsafe.Pointer) interface{} {
-	refsMu.Lock()
-	defer refsMu.Unlock()
+	if ptr == nil {
+		return nil
+	}
+
+	return (*(*cgo.Handle)(ptr)).Value()
+}
+
+// releaseRef releases a reference returned by saveRef. The pointer must not be used afterwards.
+func releaseRef(ptr unsafe.Pointer) {
+	if ptr == nil {
+		return
+	}
 
-	return refs[ptr]
+	(*(*cgo.Handle)(ptr)).Delete()
+	C.free(ptr)
+	liveRefs.Add(-1)
 }
@@ -0,0 +1,218 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #cgo CFLAGS: -I/usr/include/lpsolve/
+// #cgo LDFLAGS: -llpsolve55 -lm -ldl -lcolamd
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+	"math"
+)
+
+// Expr is a linear expression over model variables plus a constant term.
+// Expressions are immutable: all operations return a new expression.
+//
+// E.g.: the expression 2 x1 - 5 x2 + 3 x3 can be built as:
+//
+//	golpa.Sum(x1.Times(2), x2.Times(-5), x3.Times(3))
+type Expr struct {
+	vars     []*Variable
+	coefs    []float64
+	constant float64
+}
+
+// Linear is implemented by the values that can be used as terms of an
+// Expr, namely *Variable and Expr itself.
+type Linear interface {
+	Expr() Expr
+}
+
+// Relation is a linear expression bounded from below and/or above, as
+// created by Expr.LessEq, Expr.GreaterEq, Expr.Eq and Expr.Between.
+type Relation struct {
+	expr         Expr
+	lower, upper float64
+}
+
+// Expr returns an expression consisting solely of this variable.
+func (v *Variable) Expr() Expr {
+	return Expr{vars: []*Variable{v}, coefs: []float64{1}}
+}
+
+// Times returns an expression consisting of this variable multiplied by
+// the given coefficient.
+func (v *Variable) Times(coef float64) Expr {
+	return Expr{vars: []*Variable{v}, coefs: []float64{coef}}
+}
+
+// Constant returns an expression consisting only of the given constant.
+func Constant(c float64) Expr {
+	return Expr{constant: c}
+}
+
+// Sum returns the sum of the given terms.
+func Sum(terms ...Linear) Expr {
+	return Expr{}.Plus(terms...)
+}
+
+// Expr returns the expression itself, satisfying the Linear interface.
+func (e Expr) Expr() Expr {
+	return e
+}
+
+// Plus returns the sum of this expression and the given terms.
+func (e Expr) Plus(terms ...Linear) Expr {
+	res := e.copy()
+	for _, t := range terms {
+		o := t.Expr()
+		res.vars = append(res.vars, o.vars...)
+		res.coefs = append(res.coefs, o.coefs...)
+		res.constant += o.constant
+	}
+
+	return res
+}
+
+// Minus returns this expression with the given terms subtracted.
+func (e Expr) Minus(terms ...Linear) Expr {
+	res := e.copy()
+	for _, t := range terms {
+		o := t.Expr().Times(-1)
+		res.vars = append(res.vars, o.vars...)
+		res.coefs = append(res.coefs, o.coefs...)
+		res.constant += o.constant
+	}
+
+	return res
+}
+
+// Times returns this expression multiplied by the given factor.
+func (e Expr) Times(factor float64) Expr {
+	res := e.copy()
+	for i := range res.coefs {
+		res.coefs[i] *= factor
+	}
+	res.constant *= factor
+
+	return res
+}
+
+// Constant returns the constant term of the expression.
+func (e Expr) Constant() float64 {
+	return e.constant
+}
+
+// Terms returns the variables used in the expression and their respective
+// coefficients. Repeated variables are combined into a single term.
+func (e Expr) Terms() ([]*Variable, []float64) {
+	vars := make([]*Variable, 0, len(e.vars))
+	coefs := make([]float64, 0, len(e.coefs))
+	pos := make(map[*Variable]int, len(e.vars))
+
+	for i, v := range e.vars {
+		if j, ok := pos[v]; ok {
+			coefs[j] += e.coefs[i]
+			continue
+		}
+		pos[v] = len(vars)
+		vars = append(vars, v)
+		coefs = append(coefs, e.coefs[i])
+	}
+
+	return vars, coefs
+}
+
+func (e Expr) copy() Expr {
+	return Expr{
+		vars:     append([]*Variable(nil), e.vars...),
+		coefs:    append([]float64(nil), e.coefs...),
+		constant: e.constant,
+	}
+}
+
+// LessEq returns the relation "e <= rhs".
+func (e Expr) LessEq(rhs float64) Relation {
+	return Relation{expr: e, lower: math.Inf(-1), upper: rhs}
+}
+
+// GreaterEq returns the relation "e >= rhs".
+func (e Expr) GreaterEq(rhs float64) Relation {
+	return Relation{expr: e, lower: rhs, upper: math.Inf(1)}
+}
+
+// Eq returns the relation "e = rhs".
+func (e Expr) Eq(rhs float64) Relation {
+	return Relation{expr: e, lower: rhs, upper: rhs}
+}
+
+// Between returns the relation "lower <= e <= upper".
+func (e Expr) Between(lower, upper float64) Relation {
+	return Relation{expr: e, lower: lower, upper: upper}
+}
+
+// AddConstraintExpr adds a constraint given as a relation over a linear
+// expression, e.g.:
+//
+//	model.AddConstraintExpr(golpa.Sum(x1.Times(2), x2.Times(-5), x3.Times(3)).LessEq(20))
+//
+// The expression's constant term is moved to the constraint's bounds.
+func (model *Model) AddConstraintExpr(r Relation) (*Constraint, error) {
+	vars, coefs := r.expr.Terms()
+
+	return model.AddConstraint(r.lower-r.expr.constant, r.upper-r.expr.constant, vars, coefs)
+}
+
+// SetObjective replaces the model's objective function with the given
+// expression. Variables not used in the expression get an objective
+// coefficient of 0.
+func (model *Model) SetObjective(e Expr) error {
+	vars, coefs := e.Terms()
+
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	if err := model.checkOpen(); err != nil {
+		return err
+	}
+
+	row := make([]C.REAL, len(model.vars)+1)
+	colno := make([]C.int, len(model.vars)+1)
+	for i := range model.vars {
+		colno[i] = C.int(i + 1)
+	}
+
+	for i, v := range vars {
+		if v.model != model || v.index < 0 {
+			return fmt.Errorf("variable %d not part of model", i)
+		}
+		row[v.index] = C.REAL(coefs[i])
+	}
+
+	if C.set_obj_fnex(model.prob, C.int(len(model.vars)), &row[0], &colno[0]) != C.TRUE {
+		return fmt.Errorf("could not set objective function")
+	}
+
+	// row 0 is the objective function
+	C.set_rh(model.prob, 0, C.REAL(e.constant))
+
+	return nil
+}
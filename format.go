@@ -0,0 +1,274 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #cgo CFLAGS: -I/usr/include/lpsolve/
+// #cgo LDFLAGS: -llpsolve55 -lm -ldl -lcolamd
+// #include <lp_lib.h>
+// #include <stdlib.h>
+/*
+// https://golang.org/issue/19837
+extern int readLPCallback(void *userhandle, char *buf, int max_size);
+extern int readMPSCallback(void *userhandle, char *buf, int max_size);
+extern int writeCallback(void *userhandle, char *buf);
+*/
+import "C"
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unsafe"
+)
+
+// modelReader feeds the underlying library's parsers, keeping track of
+// read errors, which cannot be passed through the C callbacks.
+type modelReader struct {
+	r   *bufio.Reader
+	err error
+}
+
+// modelWriter receives the output of the underlying library's writers,
+// keeping track of write errors, which cannot be passed through the C
+// callbacks.
+type modelWriter struct {
+	w   io.Writer
+	err error
+}
+
+// ExportLP returns the model in lp format.
+func (model *Model) ExportLP() (string, error) {
+	buf := strings.Builder{}
+
+	if err := model.WriteLP(&buf); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// ExportMPS returns the model in fixed MPS format.
+func (model *Model) ExportMPS() (string, error) {
+	buf := strings.Builder{}
+
+	if err := model.WriteMPS(&buf); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// ExportFreeMPS returns the model in free MPS format.
+func (model *Model) ExportFreeMPS() (string, error) {
+	buf := strings.Builder{}
+
+	if err := model.WriteFreeMPS(&buf); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// WriteLP writes the model in lp format to the given writer.
+func (model *Model) WriteLP(w io.Writer) error {
+	model.mu.RLock()
+	defer model.mu.RUnlock()
+
+	if err := model.checkOpen(); err != nil {
+		return err
+	}
+
+	mw := &modelWriter{w: w}
+
+	ref := saveRef(mw)
+	defer releaseRef(ref)
+
+	ret := C.write_lpex(model.prob, ref, (*C.write_modeldata_func)(C.writeCallback))
+
+	return mw.result(ret, "lp")
+}
+
+// WriteMPS writes the model in fixed MPS format to the given writer.
+func (model *Model) WriteMPS(w io.Writer) error {
+	model.mu.RLock()
+	defer model.mu.RUnlock()
+
+	if err := model.checkOpen(); err != nil {
+		return err
+	}
+
+	mw := &modelWriter{w: w}
+
+	ref := saveRef(mw)
+	defer releaseRef(ref)
+
+	ret := C.write_mpsex(model.prob, ref, (*C.write_modeldata_func)(C.writeCallback))
+
+	return mw.result(ret, "MPS")
+}
+
+// WriteFreeMPS writes the model in free MPS format to the given writer.
+func (model *Model) WriteFreeMPS(w io.Writer) error {
+	model.mu.RLock()
+	defer model.mu.RUnlock()
+
+	if err := model.checkOpen(); err != nil {
+		return err
+	}
+
+	mw := &modelWriter{w: w}
+
+	ref := saveRef(mw)
+	defer releaseRef(ref)
+
+	ret := C.write_freempsex(model.prob, ref, (*C.write_modeldata_func)(C.writeCallback))
+
+	return mw.result(ret, "free MPS")
+}
+
+func (mw *modelWriter) result(ret C.MYBOOL, format string) error {
+	if mw.err != nil {
+		return fmt.Errorf("writing %s model: %w", format, mw.err)
+	}
+	if ret != C.TRUE {
+		return fmt.Errorf("model not written successfully")
+	}
+
+	return nil
+}
+
+//export writeCallback
+func writeCallback(writerPtr unsafe.Pointer, buf *C.char) C.int {
+	mw, ok := loadRef(writerPtr).(*modelWriter)
+	if !ok || mw.err != nil {
+		return 0
+	}
+
+	if _, err := io.WriteString(mw.w, C.GoString(buf)); err != nil {
+		mw.err = err
+	}
+
+	return 0
+}
+
+// ReadLP reads a model in lp format, as written by ExportLP.
+// Variables and constraints can be retrieved by name with VariableByName
+// and ConstraintByName.
+func ReadLP(r io.Reader, opts ...Option) (*Model, error) {
+	mr := &modelReader{r: bufio.NewReader(r)}
+
+	ref := saveRef(mr)
+	prob := C.read_lpex(ref, (*C.read_modeldata_func)(C.readLPCallback), C.NEUTRAL, nil)
+
+	releaseRef(ref)
+
+	return mr.newModel(prob, "lp", opts)
+}
+
+// ReadMPS reads a model in fixed MPS format.
+// Variables and constraints can be retrieved by name with VariableByName
+// and ConstraintByName.
+func ReadMPS(r io.Reader, opts ...Option) (*Model, error) {
+	mr := &modelReader{r: bufio.NewReader(r)}
+
+	ref := saveRef(mr)
+	prob := C.read_mpsex(ref, (*C.read_modeldata_func)(C.readMPSCallback), C.NEUTRAL)
+
+	releaseRef(ref)
+
+	return mr.newModel(prob, "MPS", opts)
+}
+
+// ReadFreeMPS reads a model in free MPS format.
+// Variables and constraints can be retrieved by name with VariableByName
+// and ConstraintByName.
+func ReadFreeMPS(r io.Reader, opts ...Option) (*Model, error) {
+	mr := &modelReader{r: bufio.NewReader(r)}
+
+	ref := saveRef(mr)
+	prob := C.read_freempsex(ref, (*C.read_modeldata_func)(C.readMPSCallback), C.NEUTRAL)
+
+	releaseRef(ref)
+
+	return mr.newModel(prob, "free MPS", opts)
+}
+
+func (mr *modelReader) newModel(prob *C.lprec, format string, opts []Option) (*Model, error) {
+	if mr.err != nil {
+		if prob != nil {
+			C.delete_lp(prob)
+		}
+		return nil, fmt.Errorf("reading %s model: %w", format, mr.err)
+	}
+	if prob == nil {
+		return nil, fmt.Errorf("could not parse %s model", format)
+	}
+
+	return newModelFromProb(prob, opts)
+}
+
+//export readLPCallback
+func readLPCallback(readerPtr unsafe.Pointer, buf *C.char, maxSize C.int) C.int {
+	mr, ok := loadRef(readerPtr).(*modelReader)
+	if !ok || mr.err != nil {
+		return 0
+	}
+
+	// the lp parser expects a block of up to maxSize bytes; 0 signals EOF
+	n, err := io.ReadAtLeast(mr.r, unsafe.Slice((*byte)(unsafe.Pointer(buf)), int(maxSize)), 1)
+	if err != nil && err != io.EOF {
+		mr.err = err
+	}
+
+	return C.int(n)
+}
+
+//export readMPSCallback
+func readMPSCallback(readerPtr unsafe.Pointer, buf *C.char, maxSize C.int) C.int {
+	mr, ok := loadRef(readerPtr).(*modelReader)
+	if !ok || mr.err != nil || maxSize < 1 {
+		return 0
+	}
+
+	// the MPS parser expects a single NUL-terminated line, like fgets
+	line := unsafe.Slice((*byte)(unsafe.Pointer(buf)), int(maxSize))
+	n := 0
+	for n < len(line)-1 {
+		b, err := mr.r.ReadByte()
+		if err != nil {
+			if err != io.EOF {
+				mr.err = err
+				return 0
+			}
+			break
+		}
+		line[n] = b
+		n++
+		if b == '\n' {
+			break
+		}
+	}
+	line[n] = 0
+
+	if n == 0 {
+		return 0
+	}
+
+	return 1
+}
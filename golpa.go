@@ -67,6 +67,14 @@ can be expressed with GoLPA like this:
 		// ⋮
 	}
 
+Alternatively, the objective function and the constraints can be written
+as linear expressions, closer to the mathematical notation above:
+
+	model.SetObjective(golpa.Sum(x1, x2.Times(2), x3.Times(-3)))
+	model.AddConstraintExpr(golpa.Sum(x1.Times(-1), x2, x3.Times(5.3)).Between(0, 10))
+	model.AddConstraintExpr(golpa.Sum(x1.Times(2), x2.Times(-5), x3.Times(3)).LessEq(20))
+	model.AddConstraintExpr(x2.Expr().Minus(x3.Times(8)).Eq(0))
+
 */
 package golpa
 
@@ -79,30 +87,49 @@ package golpa
 // https://golang.org/issue/19837
 extern int abortCallback(lprec *lp, void *userhandle);
 extern void logCallback(lprec *lp, void *userhandle, char *buf);
-extern int lpexCallback(void *userhandle, char *buf);
 */
 import "C"
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"math"
 	"runtime"
 	"sync"
+	"time"
 	"unsafe"
 )
 
 /* Types */
 
 type Model struct {
-	mu     sync.RWMutex
-	prob   *C.lprec
-	vars   []*Variable
-	logger Logger
+	mu          sync.RWMutex
+	prob        *C.lprec
+	vars        []*Variable
+	constraints []*Constraint
+	logger      Logger
+	slogHandler slog.Handler
+	sensitivity bool
+	// log receives the messages of the underlying problem
+	log *logSink
+
+	improvedSolutionCallback func(ImprovedSolution)
+	// basis is the starting basis for solving, if any
+	basis *Basis
+	// start is the starting solution for solving, if any
+	start map[*Variable]float64
+	// varCount is used for naming unnamed variables, which must stay
+	// unique when variables are removed
+	varCount int
+	// sosCount is used for naming special ordered sets
+	sosCount int
 }
 
+// ErrModelClosed is returned when using a model after calling Close.
+var ErrModelClosed = errors.New("model is closed")
+
 type direction C.uchar
 
 const (
@@ -124,13 +151,29 @@ func NewModel(name string, dir direction, opts ...Option) (*Model, error) {
 	C.set_lp_name(prob, c_name)
 	C.set_sense(prob, C.uchar(dir))
 
+	return newModelFromProb(prob, opts)
+}
+
+// newModelFromProb wraps an existing underlying model, creating references
+// for all of its columns and rows. The model is freed if any of the options
+// fail.
+func newModelFromProb(prob *C.lprec, opts []Option) (*Model, error) {
 	model := &Model{
 		prob:   prob,
 		logger: noopLogger{},
 	}
 
+	for i := 0; i < int(C.get_Ncolumns(prob)); i++ {
+		model.vars = append(model.vars, &Variable{model: model, index: i})
+	}
+	model.varCount = len(model.vars)
+	for i := 0; i < int(C.get_Nrows(prob)); i++ {
+		model.constraints = append(model.constraints, &Constraint{model: model, index: i})
+	}
+
 	for _, opt := range opts {
 		if err := opt(model); err != nil {
+			C.delete_lp(prob)
 			return nil, fmt.Errorf("applying model option: %w", err)
 		}
 	}
@@ -142,29 +185,109 @@ func NewModel(name string, dir direction, opts ...Option) (*Model, error) {
 
 // finishInitialization performs steps that are common to NewModel() and Clone().
 func (model *Model) finishInitialization() {
-	// disable stdoud logging and redirect to out internal logger
-	C.put_logfunc(model.prob, (*C.lphandlestr_func)(C.logCallback), saveRef(model))
-	C.set_outputfile(model.prob, C.CString(""))
+	model.log = model.configureProb(model.prob)
 
 	// plug the underlying C library's destructors to the instance of Model,
 	// otherwise we get a memory-leak of the underlying struct
 	runtime.SetFinalizer(model, finalizeModel)
 }
 
+// configureProb applies the model's settings that are not part of the
+// underlying problem itself. The returned logSink must be released once the
+// problem is deleted.
+func (model *Model) configureProb(prob *C.lprec) *logSink {
+	// disable stdoud logging and redirect to out internal logger; the
+	// callback must not reference the model itself, or it would never be
+	// garbage-collected
+	sink := &logSink{
+		logger:      model.logger,
+		slogHandler: model.slogHandler,
+	}
+	sink.ref = saveRef(sink)
+	C.put_logfunc(prob, (*C.lphandlestr_func)(C.logCallback), sink.ref)
+
+	c_empty := C.CString("")
+	defer C.free(unsafe.Pointer(c_empty))
+
+	C.set_outputfile(prob, c_empty)
+
+	if model.sensitivity {
+		C.set_sensitivity(prob, C.TRUE)
+	}
+
+	return sink
+}
+
+// copyProb returns a copy of the model's underlying problem with the given
+// solver options, to be solved independently of the model.
+func (model *Model) copyProb(opts SolverOptions) (*C.lprec, *logSink) {
+	prob := C.copy_lp(model.prob)
+
+	setSolverOptions(prob, opts)
+	C.set_verbose(prob, C.get_verbose(model.prob))
+	C.set_break_at_value(prob, C.get_break_at_value(model.prob))
+
+	return prob, model.configureProb(prob)
+}
+
 //export logCallback
-func logCallback(prob *C.lprec, modelPtr unsafe.Pointer, msg *C.char) {
-	model, ok := loadRef(modelPtr).(*Model)
+func logCallback(prob *C.lprec, sinkPtr unsafe.Pointer, msg *C.char) {
+	sink, ok := loadRef(sinkPtr).(*logSink)
 	if !ok {
 		return
 	}
 
-	model.logger.Print(C.GoString(msg))
+	if sink.slogHandler != nil {
+		sink.logRecord(prob, C.GoString(msg))
+		return
+	}
+
+	sink.logger.Print(C.GoString(msg))
 }
 
 // finalizeModel is the function registered to be called upon garbage-
 // collection of the model value
 func finalizeModel(model *Model) {
+	model.Close()
+}
+
+// Close frees the resources of the underlying model immediately, instead of
+// waiting for the model to be garbage-collected. Afterwards, methods of the
+// model and its variables and constraints return ErrModelClosed, or panic
+// with it if they cannot return errors. Results of previous solves remain
+// usable. Closing a closed model has no effect.
+func (model *Model) Close() error {
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	if model.prob == nil {
+		return nil
+	}
+
 	C.delete_lp(model.prob)
+	releaseRef(model.log.ref)
+	model.prob = nil
+	runtime.SetFinalizer(model, nil)
+
+	return nil
+}
+
+// checkOpen returns ErrModelClosed if the model has been closed. The
+// model's lock must be held.
+func (model *Model) checkOpen() error {
+	if model.prob == nil {
+		return ErrModelClosed
+	}
+
+	return nil
+}
+
+// mustBeOpen is like checkOpen, but panics, for methods that cannot return
+// errors.
+func (model *Model) mustBeOpen() {
+	if err := model.checkOpen(); err != nil {
+		panic(err)
+	}
 }
 
 // Clone returns a copy of the model.
@@ -172,22 +295,55 @@ func (model *Model) Clone() *Model {
 	model.mu.RLock()
 	defer model.mu.RUnlock()
 
+	model.mustBeOpen()
+
 	newProb := C.copy_lp(model.prob)
 	newVars := make([]*Variable, len(model.vars))
 	newModel := &Model{
-		prob:   newProb,
-		logger: model.logger,
+		prob:        newProb,
+		logger:      model.logger,
+		slogHandler: model.slogHandler,
+		sensitivity: model.sensitivity,
+
+		improvedSolutionCallback: model.improvedSolutionCallback,
+		basis:                    model.basis,
+		varCount:                 model.varCount,
+		sosCount:                 model.sosCount,
 	}
 
 	for i, v := range model.vars {
 		newVars[i] = &Variable{
-			model: newModel,
-			index: v.index,
+			model:   newModel,
+			index:   v.index,
+			unfixed: v.unfixed,
 		}
 	}
 
 	newModel.vars = newVars
 
+	if model.start != nil {
+		newModel.start = make(map[*Variable]float64, len(model.start))
+		for v, value := range model.start {
+			if v.index >= 0 {
+				newModel.start[newVars[v.index]] = value
+			}
+		}
+	}
+
+	newConstraints := make([]*Constraint, len(model.constraints))
+	for i, c := range model.constraints {
+		newConstraints[i] = &Constraint{
+			model: newModel,
+			index: c.index,
+		}
+	}
+
+	newModel.constraints = newConstraints
+
+	// not all parameters are carried over by the underlying copy
+	newModel.setSolverOptions(model.solverOptions())
+	C.set_verbose(newProb, C.get_verbose(model.prob))
+
 	newModel.finishInitialization()
 
 	return newModel
@@ -198,6 +354,8 @@ func (model *Model) Name() string {
 	model.mu.RLock()
 	defer model.mu.RUnlock()
 
+	model.mustBeOpen()
+
 	return C.GoString(C.get_lp_name(model.prob))
 }
 
@@ -206,6 +364,8 @@ func (model *Model) SetDirection(dir direction) {
 	model.mu.Lock()
 	defer model.mu.Unlock()
 
+	model.mustBeOpen()
+
 	C.set_sense(model.prob, C.uchar(dir))
 }
 
@@ -214,6 +374,8 @@ func (model *Model) Direction() direction {
 	model.mu.RLock()
 	defer model.mu.RUnlock()
 
+	model.mustBeOpen()
+
 	if C.is_maxim(model.prob) == C.TRUE {
 		return Maximize
 	} else {
@@ -227,16 +389,38 @@ func (model *Model) VariableCount() int {
 	model.mu.RLock()
 	defer model.mu.RUnlock()
 
+	model.mustBeOpen()
+
 	return int(C.get_Ncolumns(model.prob))
 }
 
 // Variables returns a new slice with the model's variables. Changes to the slice will not be reflected in the model.
-// Changes to the variables
 func (model *Model) Variables() []*Variable {
 	model.mu.RLock()
 	defer model.mu.RUnlock()
 
-	return model.vars
+	vars := make([]*Variable, len(model.vars))
+	copy(vars, model.vars)
+
+	return vars
+}
+
+// VariableByName returns the variable with the given name, if it exists.
+func (model *Model) VariableByName(name string) (*Variable, bool) {
+	model.mu.RLock()
+	defer model.mu.RUnlock()
+
+	model.mustBeOpen()
+
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	col := int(C.get_nameindex(model.prob, c_name, C.FALSE))
+	if col < 1 || col > len(model.vars) {
+		return nil, false
+	}
+
+	return model.vars[col-1], true
 }
 
 // AddVariable adds a variable to the linear programming model and
@@ -273,12 +457,22 @@ func (model *Model) AddIntegerVariable(name string) (v *Variable, err error) {
 // If varType is BinaryVariable, the bounds are ignored.
 // Empty names will automatically replaced by a unique name.
 func (model *Model) AddDefinedVariable(name string, varType VariableType, coefficient, lowerBound, upperBound float64) (v *Variable, err error) {
-	size := model.VariableCount()
+	if varType != BinaryVariable {
+		if err := checkBounds(lowerBound, upperBound); err != nil {
+			return nil, err
+		}
+	}
 
-	func() {
+	err = func() error {
 		model.mu.Lock()
 		defer model.mu.Unlock()
 
+		if err := model.checkOpen(); err != nil {
+			return err
+		}
+
+		size := len(model.vars)
+
 		v = new(Variable)
 		v.index = size
 		v.model = model
@@ -292,24 +486,60 @@ func (model *Model) AddDefinedVariable(name string, varType VariableType, coeffi
 		// C.add_column(model.prob, &coef_array[0])
 
 		if name == "" {
-			name = fmt.Sprintf("V%d", size)
+			name = fmt.Sprintf("V%d", model.varCount)
 		}
+		model.varCount++
 
 		c_name := C.CString(name)
 		defer C.free(unsafe.Pointer(c_name))
 
 		C.set_col_name(model.prob, C.int(v.index+1), c_name)
+
+		return nil
 	}()
+	if err != nil {
+		return nil, err
+	}
 
 	v.SetType(varType)
 	v.SetObjectiveCoefficient(coefficient)
 	if varType != BinaryVariable {
-		v.SetBounds(lowerBound, upperBound)
+		err = v.SetBounds(lowerBound, upperBound)
 	}
 
 	return
 }
 
+// RemoveVariable removes the given variable from the model, along with
+// its coefficients in the objective function and in all constraints.
+// All other variables remain valid. Using the removed variable
+// afterwards results in undefined behaviour.
+func (model *Model) RemoveVariable(v *Variable) error {
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	if err := model.checkOpen(); err != nil {
+		return err
+	}
+
+	if v.model != model || v.index < 0 || v.index >= len(model.vars) || model.vars[v.index] != v {
+		return fmt.Errorf("variable not part of model")
+	}
+
+	if C.del_column(model.prob, C.int(v.index+1)) != C.TRUE {
+		return fmt.Errorf("could not remove variable")
+	}
+
+	// keep the remaining variables in sync with the underlying columns
+	model.vars = append(model.vars[:v.index], model.vars[v.index+1:]...)
+	for _, other := range model.vars[v.index:] {
+		other.index--
+	}
+	v.index = -1
+
+	return nil
+}
+
 // SetObjectiveFunction defines the objective function for the model as
 // a slice of coefficients and a slice of its respective variables.
 // E.g.: an objective function of the form 2x+3y is passed as:
@@ -331,64 +561,128 @@ func (model *Model) ConstraintCount() int {
 	model.mu.RLock()
 	defer model.mu.RUnlock()
 
+	model.mustBeOpen()
+
 	return int(C.get_Nrows(model.prob))
 }
 
+// Constraints returns a new slice with the model's constraints. Changes to the slice will not be reflected in the
+// model.
+func (model *Model) Constraints() []*Constraint {
+	model.mu.RLock()
+	defer model.mu.RUnlock()
+
+	constraints := make([]*Constraint, len(model.constraints))
+	copy(constraints, model.constraints)
+
+	return constraints
+}
+
+// ConstraintByName returns the constraint with the given name, if it exists.
+func (model *Model) ConstraintByName(name string) (*Constraint, bool) {
+	model.mu.RLock()
+	defer model.mu.RUnlock()
+
+	model.mustBeOpen()
+
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	// row 0 is the objective function
+	row := int(C.get_nameindex(model.prob, c_name, C.TRUE))
+	if row < 1 || row > len(model.constraints) {
+		return nil, false
+	}
+
+	return model.constraints[row-1], true
+}
+
 // AddConstraint adds a constraint to the model as a lower and an upper
 // bounds, a slice of variables and a slice of their respective
-// coefficients.
-func (model *Model) AddConstraint(lower, upper float64, vars []*Variable, coefs []float64) error {
+// coefficients, and returns a reference to it.
+// Constraints with distinct finite lower and upper bounds are added as a
+// single range row.
+func (model *Model) AddConstraint(lower, upper float64, vars []*Variable, coefs []float64) (*Constraint, error) {
 	if len(vars) != len(coefs) {
-		return fmt.Errorf("inconsistent number of variables and coefficients: %d != %d", len(vars), len(coefs))
+		return nil, fmt.Errorf("inconsistent number of variables and coefficients: %d != %d", len(vars), len(coefs))
+	}
+	if err := checkBounds(lower, upper); err != nil {
+		return nil, err
+	}
+
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	if err := model.checkOpen(); err != nil {
+		return nil, err
 	}
 
-	row := make([]C.REAL, len(vars))
-	colno := make([]C.int, len(vars))
+	row := make([]C.REAL, len(vars)+1)
+	colno := make([]C.int, len(vars)+1)
 	for i, v := range vars {
+		if v.model != model || v.index < 0 {
+			return nil, fmt.Errorf("variable %d not part of model", i)
+		}
 		colno[i] = C.int(v.index + 1)
 		row[i] = C.REAL(coefs[i])
 	}
 
-	switch {
-	case math.IsInf(lower, 0) && math.IsInf(upper, 0):
-		// no constraints
-	case math.IsInf(lower, 0):
-		C.add_constraintex(model.prob, C.int(len(vars)), &row[0], &colno[0], C.LE, C.double(upper))
-	case math.IsInf(upper, 0):
-		C.add_constraintex(model.prob, C.int(len(vars)), &row[0], &colno[0], C.GE, C.double(lower))
-	case upper == lower:
-		C.add_constraintex(model.prob, C.int(len(vars)), &row[0], &colno[0], C.EQ, C.double(upper))
-	default:
-		C.add_constraintex(model.prob, C.int(len(vars)), &row[0], &colno[0], C.LE, C.double(upper))
-		C.add_constraintex(model.prob, C.int(len(vars)), &row[0], &colno[0], C.GE, C.double(lower))
+	// the row is added unbounded and only then gets its actual bounds
+	if C.add_constraintex(model.prob, C.int(len(vars)), &row[0], &colno[0], C.LE, C.get_infinite(model.prob)) != C.TRUE {
+		return nil, fmt.Errorf("could not add constraint")
 	}
 
-	return nil
+	c := &Constraint{
+		model: model,
+		index: len(model.constraints),
+	}
+	model.constraints = append(model.constraints, c)
+
+	setRowBounds(model.prob, c.index+1, lower, upper)
+
+	return c, nil
 }
 
-// Solve attempts to find an optimal solution to the model.
-// Information about the solution can be queried from the returned
-// SolveResult value.
-func (model *Model) Solve() (res *SolveResult, err error) {
+// RemoveConstraint removes the given constraint from the model.
+// All other constraints remain valid. Methods of the removed constraint
+// return an error or panic afterwards.
+func (model *Model) RemoveConstraint(c *Constraint) error {
 	model.mu.Lock()
 	defer model.mu.Unlock()
 
-	res = new(SolveResult)
-	res.model = model
+	if err := model.checkOpen(); err != nil {
+		return err
+	}
 
-	ret := C.solve(model.prob)
+	if c.model != model {
+		return fmt.Errorf("constraint not part of model")
+	}
+	if err := c.checkPresent(); err != nil {
+		return err
+	}
 
-	switch ret {
-	case C.OPTIMAL, C.SUBOPTIMAL:
-		res.status = SolveStatus(ret)
-		return res, nil
-	case C.INFEASIBLE, C.UNBOUNDED, C.DEGENERATE, C.NUMFAILURE,
-		C.USERABORT, C.TIMEOUT, C.PROCFAIL, C.PROCBREAK, C.FEASFOUND,
-		C.NOFEASFOUND, C.NOMEMORY:
-		return nil, SolveError(ret)
-	default:
-		panic("unrecognized result")
+	if C.del_row(model.prob, C.int(c.index+1)) != C.TRUE {
+		return fmt.Errorf("could not remove constraint")
 	}
+
+	// keep the remaining constraints in sync with the underlying rows
+	model.constraints = append(model.constraints[:c.index], model.constraints[c.index+1:]...)
+	for _, other := range model.constraints[c.index:] {
+		other.index--
+	}
+	c.index = -1
+
+	return nil
+}
+
+// Solve attempts to find an optimal solution to the model.
+// Information about the solution can be queried from the returned
+// SolveResult value.
+// The model is solved on a snapshot taken when Solve is called, so it can
+// be used from other goroutines while solving. Changes made in the
+// meantime only affect subsequent solves.
+func (model *Model) Solve() (res *SolveResult, err error) {
+	return model.solve(context.Background(), nil)
 }
 
 //export abortCallback
@@ -405,51 +699,147 @@ func abortCallback(prob *C.lprec, ctxPtr unsafe.Pointer) C.int {
 // aborted and the context error will be returned.
 // Note that if some solution has already been found, res.Status() will be SolutionSuboptimal.
 func (model *Model) SolveWithContext(ctx context.Context) (res *SolveResult, err error) {
-	C.put_abortfunc(model.prob, (*C.lphandle_intfunc)(C.abortCallback), saveRef(ctx))
-	defer C.put_abortfunc(model.prob, nil, nil)
+	return model.solve(ctx, nil)
+}
+
+// SolveWithOptions is like SolveWithContext, but uses the given solver
+// options for this solve only. The model's own options are left untouched.
+func (model *Model) SolveWithOptions(ctx context.Context, opts SolverOptions) (res *SolveResult, err error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
 
-	ret, err := model.Solve()
+	return model.solve(ctx, &opts)
+}
 
-	if errors.Is(err, ErrUserAbort) {
-		return ret, ctx.Err()
+func (model *Model) solve(ctx context.Context, opts *SolverOptions) (res *SolveResult, err error) {
+	sol, err := model.snapshot(opts)
+	if err != nil {
+		return nil, err
 	}
 
-	return ret, err
+	return model.solveSnapshot(ctx, sol)
 }
 
-//export lpexCallback
-func lpexCallback(bufPtr unsafe.Pointer, buf *C.char) C.int {
-	strBuf, ok := loadRef(bufPtr).(*bytes.Buffer)
-	if !ok {
-		return 0
+// solveSnapshot solves a snapshot taken with snapshot, which may have been
+// changed in the meantime.
+func (model *Model) solveSnapshot(ctx context.Context, sol *solution) (res *SolveResult, err error) {
+	// failed solves have no result to hold on to the snapshot; this runs
+	// after the callbacks below have been unregistered
+	defer func() {
+		if err != nil {
+			sol.mu.Lock()
+			sol.free()
+			sol.mu.Unlock()
+		}
+	}()
+
+	res = new(SolveResult)
+	res.model = model
+	res.sol = sol
+
+	prob := sol.prob
+
+	if sol.presolved {
+		sol.bounds = make([][2]float64, len(sol.constraints))
+		for i := range sol.bounds {
+			sol.bounds[i][0], sol.bounds[i][1] = rowBounds(prob, i+1)
+		}
+	}
+
+	if ctx.Done() != nil {
+		ctxRef := saveRef(ctx)
+		C.put_abortfunc(prob, (*C.lphandle_intfunc)(C.abortCallback), ctxRef)
+		defer func() {
+			C.put_abortfunc(prob, nil, nil)
+			releaseRef(ctxRef)
+		}()
 	}
 
-	strBuf.WriteString(C.GoString(buf))
+	watcher, unwatch := model.watchImprovements(sol)
+	defer unwatch()
+
+	start := time.Now()
+
+	ret := C.solve(prob)
+	elapsed := time.Since(start)
 
-	return 0
+	switch ret {
+	case C.OPTIMAL, C.SUBOPTIMAL:
+		res.status = SolveStatus(ret)
+		res.stats = solveStats(prob, elapsed, watcher.count)
+		return res, nil
+	case C.USERABORT:
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, SolveError(ret)
+	case C.INFEASIBLE, C.UNBOUNDED, C.DEGENERATE, C.NUMFAILURE,
+		C.TIMEOUT, C.PROCFAIL, C.PROCBREAK, C.FEASFOUND,
+		C.NOFEASFOUND, C.NOMEMORY, C.PRESOLVED:
+		return nil, SolveError(ret)
+	default:
+		panic("unrecognized result")
+	}
 }
 
-// ExportLP returns the model in lp format.
-func (model *Model) ExportLP() (string, error) {
+// snapshot copies the model's underlying problem, so it can be solved
+// without holding the model's lock. Solver options other than nil replace
+// the model's own for the copy.
+func (model *Model) snapshot(opts *SolverOptions) (*solution, error) {
 	model.mu.RLock()
 	defer model.mu.RUnlock()
 
-	buf := bytes.Buffer{}
+	if err := model.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	solveOpts := model.solverOptions()
+	if opts != nil {
+		solveOpts = *opts
+	}
 
-	ret := C.write_lpex(model.prob, saveRef(&buf), (*C.write_modeldata_func)(C.lpexCallback))
+	prob, log := model.copyProb(solveOpts)
+	log.solving = true
 
-	if ret != C.TRUE {
-		return "", fmt.Errorf("model not written successfully")
+	// the basis does not survive presolve, and no longer fits once
+	// variables or constraints were added or removed; the default basis is
+	// used instead
+	if model.basis != nil && solveOpts.Presolve == PresolveNone {
+		if err := model.basis.apply(prob); err != nil {
+			C.default_basis(prob)
+		}
 	}
+	if model.start != nil {
+		model.applyStart(prob)
+	}
+
+	sol := &solution{
+		prob:        prob,
+		log:         log,
+		presolved:   solveOpts.Presolve != PresolveNone,
+		vars:        make(map[*Variable]int, len(model.vars)),
+		constraints: make(map[*Constraint]int, len(model.constraints)),
+	}
+	runtime.SetFinalizer(sol, finalizeSolution)
 
-	return buf.String(), nil
+	// the model's handles may be re-indexed while the snapshot lives on
+	for i, v := range model.vars {
+		sol.vars[v] = i
+	}
+	for i, c := range model.constraints {
+		sol.constraints[c] = i
+	}
+	return sol, nil
 }
 
 // SetTarget sets the optimization target for the model.
 // The solver will return early if this target is reached.
 func (model *Model) SetTarget(target float64) {
-	model.mu.RLock()
-	defer model.mu.RUnlock()
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	model.mustBeOpen()
 
 	C.set_break_at_value(model.prob, C.double(target))
 }
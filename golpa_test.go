@@ -19,9 +19,12 @@ package golpa
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"math"
+	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -53,7 +56,7 @@ func getBigModelCopy(t *testing.T) *Model {
 			v, _ := model.AddIntegerVariable(fmt.Sprintf("x%d", i))
 			vars[i] = v
 			coefs[i] = 1
-			err := model.AddConstraint(-float64(i), float64(i), []*Variable{v}, []float64{1})
+			_, err := model.AddConstraint(-float64(i), float64(i), []*Variable{v}, []float64{1})
 			require.NoError(t, err)
 		}
 
@@ -63,6 +66,23 @@ func getBigModelCopy(t *testing.T) *Model {
 	return bigModel.Clone()
 }
 
+// addKnapsack adds a small 0-1 knapsack problem to the given maximizing
+// model and returns its items. Its optimal objective value is 30.
+func addKnapsack(t *testing.T, model *Model) []*Variable {
+	t.Helper()
+
+	values := []float64{10, 13, 7, 8, 12, 9}
+	weights := []float64{5, 7, 3, 4, 6, 5}
+	items := make([]*Variable, len(values))
+	for i, value := range values {
+		items[i], _ = model.AddDefinedVariable("", BinaryVariable, value, 0, 1)
+	}
+	_, err := model.AddConstraint(math.Inf(-1), 15, items, weights)
+	require.NoError(t, err)
+
+	return items
+}
+
 func TestInstantiation(t *testing.T) {
 	name := "test model 1"
 	model, err := NewModel(name, Maximize)
@@ -80,7 +100,7 @@ func TestClone(t *testing.T) {
 	v, err := model.AddDefinedVariable("x", ContinuousVariable, 1, 2, 3)
 	require.NoError(t, err)
 
-	err = model.AddConstraint(0, 1, []*Variable{v}, []float64{1})
+	_, err = model.AddConstraint(0, 1, []*Variable{v}, []float64{1})
 	require.NoError(t, err)
 
 	modelClone := model.Clone()
@@ -191,6 +211,1415 @@ func TestSolveLP(t *testing.T) {
 	}
 }
 
+func TestConstraint(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x, _ := model.AddVariable("x")
+	y, _ := model.AddVariable("y")
+
+	c, err := model.AddConstraint(math.Inf(-1), 20, []*Variable{x, y}, []float64{2, -5})
+	require.NoError(t, err)
+
+	c.SetName("capacity")
+	assert.Equal(t, "capacity", c.Name())
+	assert.Equal(t, LessOrEqualConstraint, c.Type())
+	assert.Equal(t, 2.0, c.Coefficient(x))
+	assert.Equal(t, -5.0, c.Coefficient(y))
+
+	require.NoError(t, c.SetCoefficient(y, 3))
+	assert.Equal(t, 3.0, c.Coefficient(y))
+
+	for _, tc := range []struct {
+		lower, upper float64
+		typ          ConstraintType
+	}{
+		{math.Inf(-1), math.Inf(1), FreeConstraint},
+		{math.Inf(-1), 10, LessOrEqualConstraint},
+		{-10, math.Inf(1), GreaterOrEqualConstraint},
+		{5, 5, EqualConstraint},
+		{-3, 7, RangeConstraint},
+	} {
+		require.NoError(t, c.SetBounds(tc.lower, tc.upper))
+		l, h := c.Bounds()
+		assert.Equal(t, tc.lower, l)
+		assert.Equal(t, tc.upper, h)
+		assert.Equal(t, tc.typ, c.Type())
+	}
+
+	assert.Error(t, c.SetBounds(1, 0))
+	assert.Equal(t, []*Constraint{c}, model.Constraints())
+}
+
+func TestConstraintChangeBetweenSolves(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, math.Inf(1))
+	c, err := model.AddConstraint(math.Inf(-1), 10, []*Variable{x}, []float64{1})
+	require.NoError(t, err)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 10, res.ObjectiveValue(), delta)
+
+	require.NoError(t, c.SetBounds(math.Inf(-1), 15))
+
+	res, err = model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 15, res.ObjectiveValue(), delta)
+}
+
+func TestRangeConstraint(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x, _ := model.AddVariable("x")
+	c, err := model.AddConstraint(-3, 7, []*Variable{x}, []float64{1})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, model.ConstraintCount())
+	assert.Equal(t, RangeConstraint, c.Type())
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 7, res.Value(x), delta)
+
+	model.SetDirection(Minimize)
+
+	res, err = model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, -3, res.Value(x), delta)
+
+	_, err = model.AddConstraint(1, 0, []*Variable{x}, []float64{1})
+	assert.Error(t, err)
+	_, err = model.AddConstraint(math.NaN(), 1, []*Variable{x}, []float64{1})
+	assert.Error(t, err)
+	assert.Error(t, c.SetBounds(0, math.NaN()))
+}
+
+func TestConstraintResults(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 3, 0, math.Inf(1))
+	y, _ := model.AddDefinedVariable("y", ContinuousVariable, 2, 0, math.Inf(1))
+
+	c1, _ := model.AddConstraint(math.Inf(-1), 4, []*Variable{x, y}, []float64{1, 1})
+	c2, _ := model.AddConstraint(math.Inf(-1), 8, []*Variable{x, y}, []float64{1, 3})
+	c3, _ := model.AddConstraint(math.Inf(-1), 3, []*Variable{x}, []float64{1})
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 11, res.ObjectiveValue(), delta)
+
+	for i, tc := range []struct {
+		c                     *Constraint
+		activity, dual, slack float64
+	}{
+		{c1, 4, 2, 0},
+		{c2, 6, 0, 2},
+		{c3, 3, 1, 0},
+	} {
+		assert.InDelta(t, tc.activity, res.ConstraintActivity(tc.c), delta, "activity of constraint %d", i)
+		assert.InDelta(t, tc.dual, res.ConstraintDual(tc.c), delta, "dual value of constraint %d", i)
+		assert.InDelta(t, tc.slack, res.Slack(tc.c), delta, "slack of constraint %d", i)
+	}
+}
+
+func TestSensitivity(t *testing.T) {
+	model, err := NewModel("test", Maximize, WithSensitivityAnalysis())
+	require.NoError(t, err)
+
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 3, 0, math.Inf(1))
+	y, _ := model.AddDefinedVariable("y", ContinuousVariable, 2, 0, math.Inf(1))
+
+	c1, _ := model.AddConstraint(math.Inf(-1), 4, []*Variable{x, y}, []float64{1, 1})
+	c2, _ := model.AddConstraint(math.Inf(-1), 8, []*Variable{x, y}, []float64{1, 3})
+	c3, _ := model.AddConstraint(math.Inf(-1), 3, []*Variable{x}, []float64{1})
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+
+	sens, err := res.Sensitivity()
+	require.NoError(t, err)
+
+	assert.InDelta(t, 2, sens.Objective[x].Lower, delta)
+	assert.Equal(t, math.Inf(1), sens.Objective[x].Upper)
+
+	assert.InDelta(t, 2, sens.Constraints[c1].Dual, delta)
+	assert.InDelta(t, 0, sens.Constraints[c2].Dual, delta)
+	assert.InDelta(t, 1, sens.Constraints[c3].Dual, delta)
+	assert.Len(t, sens.Variables, 2)
+
+	model, err = NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	res, err = model.Solve()
+	require.NoError(t, err)
+
+	_, err = res.Sensitivity()
+	assert.ErrorIs(t, err, ErrNoSensitivity)
+}
+
+func TestRemove(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x, _ := model.AddVariable("x")
+	y, _ := model.AddVariable("y")
+	z, _ := model.AddVariable("z")
+
+	cx, _ := model.AddConstraint(math.Inf(-1), 1, []*Variable{x}, []float64{1})
+	cy, _ := model.AddConstraint(math.Inf(-1), 2, []*Variable{y}, []float64{1})
+	cz, _ := model.AddConstraint(math.Inf(-1), 3, []*Variable{z}, []float64{1})
+	cx.SetName("cx")
+	cz.SetName("cz")
+
+	require.NoError(t, model.RemoveVariable(y))
+	require.NoError(t, model.RemoveConstraint(cy))
+
+	assert.Error(t, model.RemoveVariable(y))
+	assert.Error(t, model.RemoveConstraint(cy))
+
+	assert.Equal(t, 2, model.VariableCount())
+	assert.Equal(t, 2, model.ConstraintCount())
+	assert.Equal(t, []*Variable{x, z}, model.Variables())
+	assert.Equal(t, []*Constraint{cx, cz}, model.Constraints())
+
+	assert.Equal(t, "z", z.Name())
+	assert.Equal(t, "cz", cz.Name())
+	assert.Equal(t, 1.0, cz.Coefficient(z))
+	_, upper := cz.Bounds()
+	assert.Equal(t, 3.0, upper)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 4, res.ObjectiveValue(), delta)
+	assert.InDelta(t, 1, res.Value(x), delta)
+	assert.InDelta(t, 3, res.Value(z), delta)
+
+	other, err := NewModel("other", Maximize)
+	require.NoError(t, err)
+	assert.Error(t, other.RemoveVariable(x))
+
+	// removed and foreign variables cannot be used in constraints
+	_, err = model.AddConstraint(0, 1, []*Variable{y}, []float64{1})
+	assert.Error(t, err)
+	_, err = other.AddConstraintExpr(x.Expr().LessEq(1))
+	assert.Error(t, err)
+	assert.Error(t, cz.SetCoefficient(y, 1))
+	assert.Equal(t, 0.0, cz.Coefficient(y))
+
+	// removed constraints cannot be used, leaving the objective untouched
+	assert.Error(t, cy.SetBounds(0, 1))
+	assert.Error(t, cy.SetCoefficient(x, 5))
+	assert.Panics(t, func() { cy.SetName("objective") })
+	assert.Panics(t, func() { cy.Name() })
+	assert.Panics(t, func() { cy.Bounds() })
+	assert.Panics(t, func() { cy.Coefficient(x) })
+	assert.Equal(t, 1.0, x.Coefficient())
+
+	res, err = model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 4, res.ObjectiveValue(), delta)
+
+	// default names stay unique after removing variables
+	v0, _ := model.AddVariable("")
+	v1, _ := model.AddVariable("")
+	require.NoError(t, model.RemoveVariable(v0))
+	v2, _ := model.AddVariable("")
+	assert.NotEqual(t, v1.Name(), v2.Name())
+	found, ok := model.VariableByName(v2.Name())
+	assert.True(t, ok)
+	assert.Equal(t, v2, found)
+
+	clone := model.Clone()
+	v3, _ := clone.AddVariable("")
+	_, ok = model.VariableByName(v3.Name())
+	assert.False(t, ok)
+}
+
+func TestExpr(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x, _ := model.AddVariable("x")
+	y, _ := model.AddVariable("y")
+
+	e := Sum(x.Times(2), y, Constant(3)).Minus(x, Constant(1)).Times(2)
+	vars, coefs := e.Terms()
+	assert.Equal(t, []*Variable{x, y}, vars)
+	assert.Equal(t, []float64{2, 2}, coefs)
+	assert.Equal(t, 4.0, e.Constant())
+
+	c, err := model.AddConstraintExpr(e.Between(0, 10))
+	require.NoError(t, err)
+	lower, upper := c.Bounds()
+	assert.Equal(t, -4.0, lower)
+	assert.Equal(t, 6.0, upper)
+	assert.Equal(t, 2.0, c.Coefficient(x))
+}
+
+func TestExprSolve(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x1, _ := model.AddDefinedVariable("x1", ContinuousVariable, 0, 0, math.Inf(1))
+	x2, _ := model.AddDefinedVariable("x2", ContinuousVariable, 0, 0, math.Inf(1))
+	x3, _ := model.AddDefinedVariable("x3", ContinuousVariable, 0, 0, math.Inf(1))
+
+	require.NoError(t, model.SetObjective(Sum(x1, x2.Times(2)).Minus(x3).Plus(Constant(100))))
+	assert.Equal(t, 2.0, x2.Coefficient())
+	assert.Equal(t, -1.0, x3.Coefficient())
+
+	_, err = model.AddConstraintExpr(Sum(x1.Times(2), x2, x3).Between(0, 14))
+	require.NoError(t, err)
+	_, err = model.AddConstraintExpr(Sum(x1.Times(4), x2.Times(2), x3.Times(3)).LessEq(28))
+	require.NoError(t, err)
+	_, err = model.AddConstraintExpr(Sum(x1.Times(2), x2.Times(5), x3.Times(5), Constant(-30)).LessEq(0))
+	require.NoError(t, err)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+
+	expected_xs := []float64{5, 4, 0}
+	expected_obj := 113.0
+
+	assert.InDelta(t, expected_obj, res.ObjectiveValue(), delta)
+
+	for i, x := range []*Variable{x1, x2, x3} {
+		assert.InDelta(t, expected_xs[i], res.Value(x), delta)
+	}
+}
+
+func TestReadLP(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 3, 0, math.Inf(1))
+	y, _ := model.AddDefinedVariable("y", IntegerVariable, 2, 0, math.Inf(1))
+	c, _ := model.AddConstraint(math.Inf(-1), 4.5, []*Variable{x, y}, []float64{1, 1})
+	c.SetName("limit")
+	model.AddConstraint(math.Inf(-1), 3, []*Variable{x}, []float64{1})
+
+	lp, err := model.ExportLP()
+	require.NoError(t, err)
+
+	read, err := ReadLP(strings.NewReader(lp))
+	require.NoError(t, err)
+
+	assert.Equal(t, model.Direction(), read.Direction())
+	assert.Equal(t, model.VariableCount(), read.VariableCount())
+	assert.Equal(t, model.ConstraintCount(), read.ConstraintCount())
+
+	readY, ok := read.VariableByName("y")
+	require.True(t, ok)
+	assert.Equal(t, IntegerVariable, readY.Type())
+
+	readLimit, ok := read.ConstraintByName("limit")
+	require.True(t, ok)
+	_, upper := readLimit.Bounds()
+	assert.Equal(t, 4.5, upper)
+
+	_, ok = read.VariableByName("z")
+	assert.False(t, ok)
+
+	res, err := read.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 11, res.ObjectiveValue(), delta)
+	assert.InDelta(t, 1, res.Value(readY), delta)
+
+	_, err = ReadLP(strings.NewReader("this is not a model"))
+	assert.Error(t, err)
+}
+
+func TestReadMPS(t *testing.T) {
+	fixed := `NAME          TESTMPS
+ROWS
+ N  COST
+ L  LIM1
+ G  LIM2
+COLUMNS
+    X         COST      1              LIM1      1
+    X         LIM2      1
+    Y         COST      2              LIM1      1
+RHS
+    RHS       LIM1      5              LIM2      1
+BOUNDS
+ UP BND       X         4
+ UP BND       Y         1
+ENDATA
+`
+	free := `NAME TESTMPS
+ROWS
+ N COST
+ L LIM1
+ G LIM2
+COLUMNS
+ X COST 1 LIM1 1
+ X LIM2 1
+ Y COST 2 LIM1 1
+RHS
+ RHS LIM1 5 LIM2 1
+BOUNDS
+ UP BND X 4
+ UP BND Y 1
+ENDATA
+`
+
+	for name, read := range map[string]func() (*Model, error){
+		"fixed": func() (*Model, error) { return ReadMPS(strings.NewReader(fixed)) },
+		"free":  func() (*Model, error) { return ReadFreeMPS(strings.NewReader(free)) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			model, err := read()
+			require.NoError(t, err)
+
+			assert.Equal(t, Minimize, model.Direction())
+			assert.Equal(t, 2, model.VariableCount())
+			assert.Equal(t, 2, model.ConstraintCount())
+
+			x, ok := model.VariableByName("X")
+			require.True(t, ok)
+			_, upper := x.Bounds()
+			assert.Equal(t, 4.0, upper)
+
+			lim2, ok := model.ConstraintByName("LIM2")
+			require.True(t, ok)
+			assert.Equal(t, GreaterOrEqualConstraint, lim2.Type())
+
+			res, err := model.Solve()
+			require.NoError(t, err)
+			assert.InDelta(t, 1, res.ObjectiveValue(), delta)
+			assert.InDelta(t, 1, res.Value(x), delta)
+		})
+	}
+}
+
+func TestExportMPS(t *testing.T) {
+	model, err := NewModel("test", Minimize)
+	require.NoError(t, err)
+
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 3, 0, math.Inf(1))
+	y, _ := model.AddDefinedVariable("y", IntegerVariable, 2, 0, math.Inf(1))
+	c, _ := model.AddConstraint(4.5, math.Inf(1), []*Variable{x, y}, []float64{1, 1})
+	c.SetName("limit")
+	model.AddConstraint(-1, 3, []*Variable{x}, []float64{1})
+
+	fixed, err := model.ExportMPS()
+	require.NoError(t, err)
+	free, err := model.ExportFreeMPS()
+	require.NoError(t, err)
+
+	fromFixed, err := ReadMPS(strings.NewReader(fixed))
+	require.NoError(t, err)
+	fromFree, err := ReadFreeMPS(strings.NewReader(free))
+	require.NoError(t, err)
+
+	for _, read := range []*Model{fromFixed, fromFree} {
+		assert.Equal(t, model.VariableCount(), read.VariableCount())
+		assert.Equal(t, model.ConstraintCount(), read.ConstraintCount())
+
+		_, ok := read.ConstraintByName("limit")
+		assert.True(t, ok)
+
+		res, err := read.Solve()
+		require.NoError(t, err)
+		assert.InDelta(t, 9.5, res.ObjectiveValue(), delta)
+	}
+
+	assert.Error(t, model.WriteLP(failingWriter{}))
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, fmt.Errorf("write failed")
+}
+
+func TestSolverOptions(t *testing.T) {
+	opts := DefaultSolverOptions()
+	opts.Timeout = 1500 * time.Millisecond
+	opts.EpsilonInt = 1e-6
+	opts.MIPGapAbsolute = 0.5
+	opts.MIPGapRelative = 0.01
+	opts.Scaling = ScaleMean | ScaleLogarithmic
+	opts.Pivoting = PivotDantzig | PivotAdaptive
+	opts.Branching = BranchFloor
+	opts.DepthLimit = -20
+	opts.Improve = ImproveDualFeasibility | ImproveThetaGap
+
+	model, err := NewModel("test", Maximize, WithSolverOptions(opts))
+	require.NoError(t, err)
+
+	// timeouts are rounded up to whole seconds
+	expected := opts
+	expected.Timeout = 2 * time.Second
+
+	assert.Equal(t, expected, model.SolverOptions())
+	assert.Equal(t, expected, model.Clone().SolverOptions())
+
+	opts.EpsilonPivot = 0
+	assert.Error(t, model.SetSolverOptions(opts))
+	_, err = NewModel("test", Maximize, WithSolverOptions(SolverOptions{}))
+	assert.Error(t, err)
+}
+
+func TestSolveWithOptions(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x, _ := model.AddDefinedVariable("x", IntegerVariable, 1, 0, 10)
+	model.AddConstraint(math.Inf(-1), 7.5, []*Variable{x}, []float64{1})
+
+	before := model.SolverOptions()
+
+	opts := DefaultSolverOptions()
+	opts.Branching = BranchCeiling
+	opts.DepthLimit = 5
+
+	res, err := model.SolveWithOptions(context.Background(), opts)
+	require.NoError(t, err)
+	assert.InDelta(t, 7, res.Value(x), delta)
+
+	assert.Equal(t, before, model.SolverOptions())
+}
+
+func TestPresolve(t *testing.T) {
+	opts := DefaultSolverOptions()
+	opts.Presolve = PresolveRows | PresolveCols | PresolveLinDep | PresolveDuals
+
+	model, err := NewModel("test", Maximize, WithSolverOptions(opts))
+	require.NoError(t, err)
+	assert.Equal(t, opts.Presolve, model.SolverOptions().Presolve)
+
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 10)
+	y, _ := model.AddDefinedVariable("y", ContinuousVariable, 2, 0, math.Inf(1))
+	z, _ := model.AddDefinedVariable("z", ContinuousVariable, 0.5, 0, math.Inf(1))
+
+	// singleton rows are removed by presolve, fixing y
+	fixY, _ := model.AddConstraint(3, 3, []*Variable{y}, []float64{1})
+	limit, _ := model.AddConstraint(math.Inf(-1), 8, []*Variable{x, z}, []float64{1, 1})
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+
+	// the model itself is not reduced
+	assert.Equal(t, 3, model.VariableCount())
+	assert.Equal(t, 2, model.ConstraintCount())
+
+	assert.InDelta(t, 14, res.ObjectiveValue(), delta)
+	assert.InDelta(t, 8, res.Value(x), delta)
+	assert.InDelta(t, 3, res.Value(y), delta)
+	assert.InDelta(t, 0, res.Value(z), delta)
+	assert.InDelta(t, 3, res.ConstraintActivity(fixY), delta)
+	assert.InDelta(t, 8, res.ConstraintActivity(limit), delta)
+
+	require.NoError(t, limit.SetBounds(math.Inf(-1), 9))
+
+	res, err = model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 15, res.ObjectiveValue(), delta)
+	assert.InDelta(t, 3, res.Value(y), delta)
+
+	// duals are mapped to the variables and constraints left by presolve
+	opts.Presolve |= PresolveSensDuals
+	model, err = NewModel("test", Maximize, WithSolverOptions(opts), WithSensitivityAnalysis())
+	require.NoError(t, err)
+
+	x, _ = model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 10)
+	y, _ = model.AddDefinedVariable("y", ContinuousVariable, 2, 0, math.Inf(1))
+	z, _ = model.AddDefinedVariable("z", ContinuousVariable, 0.5, 0, math.Inf(1))
+	_, _ = model.AddConstraint(3, 3, []*Variable{y}, []float64{1})
+	limit, _ = model.AddConstraint(math.Inf(-1), 8, []*Variable{x, z}, []float64{1, 1})
+
+	res, err = model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 1, res.ConstraintDual(limit), delta)
+	assert.InDelta(t, 0, res.DualValue(x), delta)
+	assert.InDelta(t, 0.5, math.Abs(res.DualValue(z)), delta)
+
+	sens, err := res.Sensitivity()
+	require.NoError(t, err)
+	assert.InDelta(t, 1, sens.Constraints[limit].Dual, delta)
+	assert.InDelta(t, 0.5, sens.Objective[x].Lower, delta)
+	assert.InDelta(t, 0, sens.Variables[x].Dual, delta)
+	assert.InDelta(t, 0.5, math.Abs(sens.Variables[z].Dual), delta)
+}
+
+func TestImprovedSolutionCallback(t *testing.T) {
+	var solutions []ImprovedSolution
+
+	model, err := NewModel("test", Maximize, WithImprovedSolutionCallback(func(s ImprovedSolution) {
+		solutions = append(solutions, s)
+	}))
+	require.NoError(t, err)
+
+	items := addKnapsack(t, model)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+
+	require.NotEmpty(t, solutions)
+	for i, s := range solutions {
+		assert.GreaterOrEqual(t, s.RootBound+delta, s.Objective)
+		if i > 0 {
+			assert.Greater(t, s.Objective, solutions[i-1].Objective)
+		}
+
+		objective := 0.0
+		for _, item := range items {
+			objective += item.Coefficient() * s.Value(item)
+		}
+		assert.InDelta(t, s.Objective, objective, delta)
+	}
+
+	last := solutions[len(solutions)-1]
+	assert.InDelta(t, res.ObjectiveValue(), last.Objective, delta)
+	for _, item := range items {
+		assert.InDelta(t, res.Value(item), last.Value(item), delta)
+	}
+}
+
+func TestSolveStats(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	items := addKnapsack(t, model)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+
+	stats := res.Stats()
+	assert.Positive(t, stats.Iterations)
+	assert.Positive(t, stats.Nodes)
+	assert.Positive(t, stats.Elapsed)
+	assert.GreaterOrEqual(t, stats.ImprovedSolutions, 1)
+	assert.GreaterOrEqual(t, stats.RootGapAbsolute, 0.0)
+	assert.InDelta(t, stats.RootGapAbsolute/(1+math.Abs(res.ObjectiveValue())), stats.RootGapRelative, delta)
+
+	// without integer variables the relaxation is the solution
+	for _, item := range items {
+		item.SetType(ContinuousVariable)
+	}
+
+	res, err = model.Solve()
+	require.NoError(t, err)
+
+	stats = res.Stats()
+	assert.InDelta(t, 0, stats.RootGapAbsolute, delta)
+}
+
+func TestSlogHandler(t *testing.T) {
+	handler := &recordingHandler{}
+
+	model, err := NewModel("test", Maximize, WithSlogHandler(handler), WithVerbosity(VerbosityDetailed))
+	require.NoError(t, err)
+	assert.Equal(t, VerbosityDetailed, model.Verbosity())
+
+	x, _ := model.AddDefinedVariable("x", IntegerVariable, 1, 0, 10)
+	_, err = model.AddConstraint(math.Inf(-1), 7.5, []*Variable{x}, []float64{1})
+	require.NoError(t, err)
+
+	_, err = model.Solve()
+	require.NoError(t, err)
+
+	require.NotEmpty(t, handler.records)
+	for _, r := range handler.records {
+		assert.Equal(t, slog.LevelInfo, r.Level)
+		assert.NotContains(t, r.Message, "\n")
+
+		attrs := map[string]string{}
+		r.Attrs(func(a slog.Attr) bool {
+			attrs[a.Key] = a.Value.String()
+			return true
+		})
+		assert.Equal(t, "test", attrs["model"])
+		assert.Equal(t, "solve", attrs["phase"])
+		assert.Equal(t, "detailed", attrs["verbosity"])
+	}
+
+	// critical messages only
+	require.NoError(t, model.SetVerbosity(VerbosityCritical))
+	handler.records = nil
+	_, err = model.Solve()
+	require.NoError(t, err)
+	assert.Empty(t, handler.records)
+
+	assert.Error(t, model.SetVerbosity(VerbosityFull+1))
+	_, err = NewModel("test", Maximize, WithVerbosity(-1))
+	assert.Error(t, err)
+}
+
+// recordingHandler is a slog.Handler that keeps all records.
+type recordingHandler struct {
+	records []slog.Record
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	h.records = append(h.records, r)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *recordingHandler) WithGroup(string) slog.Handler { return h }
+
+func TestModelsAreCollected(t *testing.T) {
+	before := liveRefs.Load()
+
+	func() {
+		model, err := NewModel("test", Maximize)
+		require.NoError(t, err)
+
+		x, _ := model.AddDefinedVariable("x", IntegerVariable, 1, 0, 10)
+		_, err = model.AddConstraint(math.Inf(-1), 7.5, []*Variable{x}, []float64{1})
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		_, err = model.SolveWithContext(ctx)
+		require.NoError(t, err)
+
+		opts := DefaultSolverOptions()
+		opts.Presolve = PresolveRows | PresolveCols
+		_, err = model.SolveWithOptions(ctx, opts)
+		require.NoError(t, err)
+
+		lp, err := model.ExportLP()
+		require.NoError(t, err)
+
+		_, err = ReadLP(strings.NewReader(lp))
+		require.NoError(t, err)
+
+		_ = model.Clone()
+	}()
+
+	// finalizers run asynchronously after the objects were found unreachable
+	for i := 0; i < 50 && liveRefs.Load() > before; i++ {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.LessOrEqual(t, liveRefs.Load(), before)
+}
+
+func TestClose(t *testing.T) {
+	before := liveRefs.Load()
+
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 10)
+	c, err := model.AddConstraint(math.Inf(-1), 7.5, []*Variable{x}, []float64{1})
+	require.NoError(t, err)
+
+	require.NoError(t, model.Close())
+	require.NoError(t, model.Close())
+	assert.LessOrEqual(t, liveRefs.Load(), before)
+
+	_, err = model.Solve()
+	assert.ErrorIs(t, err, ErrModelClosed)
+	_, err = model.AddVariable("y")
+	assert.ErrorIs(t, err, ErrModelClosed)
+	_, err = model.AddConstraint(0, 1, []*Variable{x}, []float64{1})
+	assert.ErrorIs(t, err, ErrModelClosed)
+	assert.ErrorIs(t, c.SetBounds(0, 1), ErrModelClosed)
+	assert.ErrorIs(t, model.RemoveVariable(x), ErrModelClosed)
+	_, err = model.ExportLP()
+	assert.ErrorIs(t, err, ErrModelClosed)
+
+	assert.PanicsWithValue(t, ErrModelClosed, func() { model.VariableCount() })
+	assert.PanicsWithValue(t, ErrModelClosed, func() { x.Bounds() })
+}
+
+func TestCloseResult(t *testing.T) {
+	model, err := NewModel("test", Maximize, WithSensitivityAnalysis())
+	require.NoError(t, err)
+
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 10)
+	c, err := model.AddConstraint(math.Inf(-1), 7.5, []*Variable{x}, []float64{1})
+	require.NoError(t, err)
+
+	before := liveRefs.Load()
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 7.5, res.Value(x), delta)
+
+	require.NoError(t, res.Close())
+	require.NoError(t, res.Close())
+	assert.LessOrEqual(t, liveRefs.Load(), before)
+
+	assert.Equal(t, SolutionOptimal, res.Status())
+	assert.NotPanics(t, func() { res.Stats() })
+
+	_, err = res.Basis()
+	assert.ErrorIs(t, err, ErrResultClosed)
+	_, err = res.Sensitivity()
+	assert.ErrorIs(t, err, ErrResultClosed)
+	assert.PanicsWithValue(t, ErrResultClosed, func() { res.Value(x) })
+	assert.PanicsWithValue(t, ErrResultClosed, func() { res.ConstraintActivity(c) })
+
+	// the model is unaffected
+	res, err = model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 7.5, res.ObjectiveValue(), delta)
+}
+
+func TestReadWhileSolving(t *testing.T) {
+	model := getBigModelCopy(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		_, err := model.SolveWithContext(ctx)
+		done <- err
+	}()
+
+	// give the solver time to start
+	time.Sleep(100 * time.Millisecond)
+
+	read := make(chan struct{})
+	go func() {
+		model.Name()
+		model.VariableCount()
+		model.Variables()[0].Bounds()
+		close(read)
+	}()
+
+	select {
+	case <-read:
+	case err := <-done:
+		t.Fatalf("solve finished before reads: %v", err)
+	case <-time.After(time.Second):
+		t.Fatal("reads blocked by solve")
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestChangeAfterSolve(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 10)
+	y, _ := model.AddDefinedVariable("y", ContinuousVariable, 2, 0, 10)
+	c, _ := model.AddConstraint(math.Inf(-1), 15, []*Variable{x, y}, []float64{1, 1})
+	d, _ := model.AddConstraint(math.Inf(-1), 8, []*Variable{y}, []float64{1})
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+
+	// re-indexes the remaining handles
+	require.NoError(t, model.RemoveVariable(x))
+	require.NoError(t, model.RemoveConstraint(c))
+	require.NoError(t, d.SetBounds(math.Inf(-1), 9))
+	z, _ := model.AddVariable("z")
+
+	assert.InDelta(t, 23, res.ObjectiveValue(), delta)
+	assert.InDelta(t, 7, res.Value(x), delta)
+	assert.InDelta(t, 8, res.Value(y), delta)
+	assert.InDelta(t, 15, res.ConstraintActivity(c), delta)
+	assert.InDelta(t, 8, res.ConstraintActivity(d), delta)
+	assert.InDelta(t, 0, res.Slack(d), delta)
+	assert.True(t, math.IsNaN(res.Value(z)))
+}
+
+func TestSolvePortfolio(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	items := addKnapsack(t, model)
+
+	ceiling := DefaultSolverOptions()
+	ceiling.Branching = BranchCeiling
+	floor := DefaultSolverOptions()
+	floor.Branching = BranchFloor
+	presolved := DefaultSolverOptions()
+	presolved.Presolve = PresolveRows | PresolveCols
+
+	res, err := SolvePortfolio(context.Background(), model, []SolverOptions{ceiling, floor, presolved})
+	require.NoError(t, err)
+	assert.Equal(t, SolutionOptimal, res.Status())
+	assert.InDelta(t, 30, res.ObjectiveValue(), delta)
+
+	_, err = SolvePortfolio(context.Background(), model, nil)
+	assert.Error(t, err)
+
+	_, err = model.AddConstraint(16, math.Inf(1), items[:1], []float64{1})
+	require.NoError(t, err)
+	_, err = SolvePortfolio(context.Background(), model, []SolverOptions{ceiling, floor})
+	assert.ErrorIs(t, err, ErrModelInfeasible)
+}
+
+func TestSolvePortfolioTimeout(t *testing.T) {
+	model := getBigModelCopy(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := SolvePortfolio(ctx, model, []SolverOptions{DefaultSolverOptions(), DefaultSolverOptions()})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestSolveScenarios(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 10)
+	y, _ := model.AddDefinedVariable("y", ContinuousVariable, 2, 0, 10)
+	c, _ := model.AddConstraint(math.Inf(-1), 15, []*Variable{x, y}, []float64{1, 1})
+	_, _ = model.AddConstraint(math.Inf(-1), 8, []*Variable{y}, []float64{1})
+
+	other, err := NewModel("other", Maximize)
+	require.NoError(t, err)
+	foreign, _ := other.AddVariable("foreign")
+
+	scenarios := []Scenario{
+		{Name: "base"},
+		{Name: "tight-x", VariableBounds: map[*Variable]Bounds{x: {0, 5}}},
+		{Name: "rhs", ConstraintBounds: map[*Constraint]Bounds{c: {math.Inf(-1), 12}}},
+		{Name: "objective", ObjectiveCoefficients: map[*Variable]float64{y: 0.5}},
+		{Name: "infeasible", VariableBounds: map[*Variable]Bounds{x: {16, 20}}},
+		{Name: "foreign", VariableBounds: map[*Variable]Bounds{foreign: {0, 1}}},
+		{Name: "nan", ConstraintBounds: map[*Constraint]Bounds{c: {math.NaN(), 12}}},
+	}
+
+	for _, parallelism := range []int{1, 3} {
+		results, err := model.SolveScenarios(context.Background(), scenarios, parallelism)
+		require.NoError(t, err)
+		require.Len(t, results, len(scenarios))
+
+		for name, expected := range map[string][3]float64{
+			"base":      {23, 7, 8},
+			"tight-x":   {21, 5, 8},
+			"rhs":       {20, 4, 8},
+			"objective": {12.5, 10, 5},
+		} {
+			r := results[name]
+			require.NoError(t, r.Err, name)
+			assert.InDelta(t, expected[0], r.Result.ObjectiveValue(), delta, name)
+			assert.InDelta(t, expected[1], r.Result.Value(x), delta, name)
+			assert.InDelta(t, expected[2], r.Result.Value(y), delta, name)
+		}
+
+		assert.ErrorIs(t, results["infeasible"].Err, ErrModelInfeasible)
+		assert.Error(t, results["foreign"].Err)
+		assert.Error(t, results["nan"].Err)
+	}
+
+	// the model itself is unchanged
+	lower, upper := x.Bounds()
+	assert.Equal(t, 0.0, lower)
+	assert.Equal(t, 10.0, upper)
+	assert.Equal(t, 2.0, y.Coefficient())
+
+	_, err = model.SolveScenarios(context.Background(), []Scenario{{Name: "a"}, {Name: "a"}}, 1)
+	assert.Error(t, err)
+}
+
+func TestBasis(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 10)
+	y, _ := model.AddDefinedVariable("y", ContinuousVariable, 2, 0, 10)
+	c, _ := model.AddConstraint(math.Inf(-1), 15, []*Variable{x, y}, []float64{1, 1})
+	_, _ = model.AddConstraint(math.Inf(-1), 8, []*Variable{y}, []float64{1})
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+
+	basis, err := res.Basis()
+	require.NoError(t, err)
+
+	require.NoError(t, c.SetBounds(math.Inf(-1), 14))
+	require.NoError(t, model.SetBasis(basis))
+
+	warm, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 22, warm.ObjectiveValue(), delta)
+	assert.LessOrEqual(t, warm.Stats().Iterations, res.Stats().Iterations)
+
+	filename := filepath.Join(t.TempDir(), "test.bas")
+	require.NoError(t, warm.WriteBasis(filename))
+	require.NoError(t, model.ReadBasis(filename))
+
+	res, err = model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 22, res.ObjectiveValue(), delta)
+
+	require.NoError(t, model.SetStartingSolution(map[*Variable]float64{x: 6, y: 8}))
+	res, err = model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 22, res.ObjectiveValue(), delta)
+
+	// the basis no longer fits, so solving starts from the default basis
+	z, _ := model.AddDefinedVariable("z", ContinuousVariable, 1, 0, 1)
+	res, err = model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 23, res.ObjectiveValue(), delta)
+	assert.Error(t, model.SetBasis(basis))
+
+	require.NoError(t, z.SetUpperBound(math.Inf(1)))
+	model.ResetBasis()
+	_, err = model.Solve()
+	assert.ErrorIs(t, err, ErrModelUnbounded)
+}
+
+func TestStartingSolution(t *testing.T) {
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+	items := addKnapsack(t, model)
+
+	for _, start := range []map[*Variable]float64{
+		// optimal
+		{items[0]: 1, items[1]: 1, items[2]: 1},
+		// feasible
+		{items[0]: 1, items[2]: 1, items[3]: 1},
+		// infeasible
+		{items[0]: 1, items[1]: 1, items[4]: 1},
+		// fractional
+		{items[0]: 0.5, items[1]: 1},
+	} {
+		require.NoError(t, model.SetStartingSolution(start))
+
+		res, err := model.Solve()
+		require.NoError(t, err)
+		assert.InDelta(t, 30, res.ObjectiveValue(), delta)
+
+		res, err = model.Clone().Solve()
+		require.NoError(t, err)
+		assert.InDelta(t, 30, res.ObjectiveValue(), delta)
+	}
+
+	assert.Error(t, model.SetStartingSolution(map[*Variable]float64{items[0]: math.NaN()}))
+	assert.Error(t, model.SetStartingSolution(map[*Variable]float64{items[0]: math.Inf(1)}))
+}
+
+func TestSOS(t *testing.T) {
+	for _, tc := range []struct {
+		sosType  SOSType
+		coefs    []float64
+		capacity float64
+		expected float64
+	}{
+		{sosType: SOS1, coefs: []float64{1, 2, 3}, capacity: 15, expected: 30},
+		{sosType: SOS2, coefs: []float64{1, 3, 2, 4}, capacity: 20, expected: 60},
+	} {
+		model, err := NewModel("test", Maximize)
+		require.NoError(t, err)
+
+		vars := make([]*Variable, len(tc.coefs))
+		ones := make([]float64, len(tc.coefs))
+		for i, coef := range tc.coefs {
+			vars[i], _ = model.AddDefinedVariable("", ContinuousVariable, coef, 0, 10)
+			ones[i] = 1
+		}
+		_, err = model.AddConstraint(math.Inf(-1), tc.capacity, vars, ones)
+		require.NoError(t, err)
+
+		require.NoError(t, model.AddSOS(tc.sosType, 1, vars, nil))
+
+		res, err := model.Solve()
+		require.NoError(t, err)
+		assert.InDelta(t, tc.expected, res.ObjectiveValue(), delta)
+
+		nonZero := 0
+		for _, v := range vars {
+			if res.Value(v) > delta {
+				nonZero++
+			}
+		}
+		assert.LessOrEqual(t, nonZero, int(tc.sosType))
+	}
+
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+	x, _ := model.AddVariable("x")
+
+	assert.Error(t, model.AddSOS(3, 1, []*Variable{x}, nil))
+	assert.Error(t, model.AddSOS(SOS1, 1, nil, nil))
+	assert.Error(t, model.AddSOS(SOS1, 1, []*Variable{x}, []float64{1, 2}))
+
+	y, _ := model.AddVariable("y")
+	assert.Error(t, model.AddSOS(SOS2, 1, []*Variable{x, y}, []float64{1, 1}))
+	assert.Error(t, model.AddSOS(SOS2, 1, []*Variable{x, y}, []float64{1, math.NaN()}))
+}
+
+func TestSemiContinuous(t *testing.T) {
+	for _, tc := range []struct {
+		varType      VariableType
+		lower, upper float64
+		otherCost    float64
+		demand       float64
+		expected     float64
+	}{
+		// cheaper to produce a whole lot than to buy
+		{varType: SemiContinuousVariable, lower: 50, upper: 200, otherCost: 5, demand: 30, expected: 50},
+		// cheaper to buy than to produce a whole lot
+		{varType: SemiContinuousVariable, lower: 50, upper: 200, otherCost: 0.5, demand: 30, expected: 0},
+		{varType: SemiContinuousIntegerVariable, lower: 2.5, upper: 10, otherCost: 5, demand: 1, expected: 3},
+	} {
+		model, err := NewModel("test", Minimize)
+		require.NoError(t, err)
+
+		x, err := model.AddDefinedVariable("x", tc.varType, 1, tc.lower, tc.upper)
+		require.NoError(t, err)
+		assert.Equal(t, tc.varType, x.Type())
+
+		y, _ := model.AddDefinedVariable("y", ContinuousVariable, tc.otherCost, 0, math.Inf(1))
+		_, err = model.AddConstraint(tc.demand, math.Inf(1), []*Variable{x, y}, []float64{1, 1})
+		require.NoError(t, err)
+
+		res, err := model.Solve()
+		require.NoError(t, err)
+		assert.InDelta(t, tc.expected, res.Value(x), delta)
+	}
+
+	model, err := NewModel("test", Minimize)
+	require.NoError(t, err)
+	x, _ := model.AddDefinedVariable("x", SemiContinuousVariable, 1, 1, 2)
+
+	for _, varType := range []VariableType{ContinuousVariable, IntegerVariable, SemiContinuousIntegerVariable, BinaryVariable, SemiContinuousVariable} {
+		x.SetType(varType)
+		assert.Equal(t, varType, x.Type())
+	}
+}
+
+func TestVariableBounds(t *testing.T) {
+	model, err := NewModel("test", Minimize)
+	require.NoError(t, err)
+
+	v, err := model.AddDefinedVariable("v", ContinuousVariable, 1, 0, 1)
+	require.NoError(t, err)
+
+	values := []float64{math.Inf(-1), -5, 0, 5, math.Inf(1), math.NaN()}
+	for _, lower := range values {
+		for _, upper := range values {
+			name := fmt.Sprintf("[%v,%v]", lower, upper)
+			valid := lower <= upper && !math.IsInf(lower, 1) && !math.IsInf(upper, -1)
+
+			require.NoError(t, v.SetBounds(-1, 1))
+
+			err := v.SetBounds(lower, upper)
+			l, h := v.Bounds()
+			if !valid {
+				assert.Error(t, err, name)
+				assert.Equal(t, -1.0, l, name)
+				assert.Equal(t, 1.0, h, name)
+				continue
+			}
+
+			require.NoError(t, err, name)
+			assert.Equal(t, lower, l, name)
+			assert.Equal(t, upper, h, name)
+		}
+	}
+
+	require.NoError(t, v.SetBounds(-5, 5))
+	require.NoError(t, v.SetLowerBound(math.Inf(-1)))
+	l, h := v.Bounds()
+	assert.Equal(t, math.Inf(-1), l)
+	assert.Equal(t, 5.0, h)
+	require.NoError(t, v.SetUpperBound(-3))
+	l, h = v.Bounds()
+	assert.Equal(t, math.Inf(-1), l)
+	assert.Equal(t, -3.0, h)
+	assert.Error(t, v.SetLowerBound(0))
+	assert.Error(t, v.SetUpperBound(math.Inf(-1)))
+
+	require.NoError(t, v.SetFree())
+	l, h = v.Bounds()
+	assert.Equal(t, math.Inf(-1), l)
+	assert.Equal(t, math.Inf(1), h)
+
+	// a free variable can be negative
+	_, err = model.AddConstraint(-7, math.Inf(1), []*Variable{v}, []float64{1})
+	require.NoError(t, err)
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, -7, res.Value(v), delta)
+
+	require.NoError(t, v.SetBounds(2, 8))
+	require.NoError(t, v.Fix(4))
+	require.NoError(t, v.Fix(6))
+	l, h = v.Bounds()
+	assert.Equal(t, 6.0, l)
+	assert.Equal(t, 6.0, h)
+
+	require.NoError(t, v.Unfix())
+	l, h = v.Bounds()
+	assert.Equal(t, 2.0, l)
+	assert.Equal(t, 8.0, h)
+	assert.Error(t, v.Unfix())
+
+	require.NoError(t, v.Fix(4))
+	require.NoError(t, v.SetUpperBound(5))
+	assert.Error(t, v.Unfix())
+	assert.Error(t, v.Fix(math.Inf(1)))
+
+	_, err = model.AddDefinedVariable("w", ContinuousVariable, 1, 1, 0)
+	assert.Error(t, err)
+}
+
+func TestPiecewiseLinear(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		dir         direction
+		use         FunctionUse
+		values      []float64
+		lower       float64
+		upper       float64
+		expected    float64
+		expectedSOS int
+	}{
+		// convex costs are minimized without SOS
+		{name: "convex", dir: Minimize, use: MinimizedFunction, values: []float64{0, 10, 40}, lower: 15, upper: 20, expected: 25, expectedSOS: 0},
+		{name: "convex exact", dir: Minimize, use: ExactFunction, values: []float64{0, 10, 40}, lower: 15, upper: 20, expected: 25, expectedSOS: 1},
+		// volume discounts need SOS, since the relaxation would skip f(10)
+		{name: "discount", dir: Minimize, use: MinimizedFunction, values: []float64{0, 20, 30}, lower: 15, upper: 20, expected: 25, expectedSOS: 1},
+		{name: "concave", dir: Maximize, use: MaximizedFunction, values: []float64{0, 20, 30}, lower: 0, upper: 5, expected: 10, expectedSOS: 0},
+		{name: "convex maximized", dir: Maximize, use: MaximizedFunction, values: []float64{0, 10, 40}, lower: 0, upper: 15, expected: 25, expectedSOS: 1},
+	} {
+		model, err := NewModel("test", tc.dir)
+		require.NoError(t, err)
+
+		x, _ := model.AddDefinedVariable("x", ContinuousVariable, 0, tc.lower, tc.upper)
+
+		y, err := model.AddPiecewiseLinear(x, []float64{0, 10, 20}, tc.values, tc.use)
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expectedSOS, model.sosCount, tc.name)
+		assert.Equal(t, 0.0, y.Coefficient(), tc.name)
+		y.SetObjectiveCoefficient(1)
+
+		res, err := model.Solve()
+		require.NoError(t, err, tc.name)
+		assert.InDelta(t, tc.expected, res.Value(y), delta, tc.name)
+	}
+
+	// the function value only used in a constraint: f(x) <= 15
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 20)
+
+	y, err := model.AddPiecewiseLinear(x, []float64{0, 10, 20}, []float64{0, 10, 40}, ExactFunction)
+	require.NoError(t, err)
+	_, err = model.AddConstraintExpr(y.Expr().LessEq(15))
+	require.NoError(t, err)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 10+5.0/3, res.ObjectiveValue(), delta)
+	assert.InDelta(t, 15, res.Value(y), delta)
+
+	_, err = model.AddPiecewiseLinear(x, []float64{0, 1}, []float64{0}, ExactFunction)
+	assert.Error(t, err)
+	_, err = model.AddPiecewiseLinear(x, []float64{0}, []float64{0}, ExactFunction)
+	assert.Error(t, err)
+	_, err = model.AddPiecewiseLinear(x, []float64{1, 0}, []float64{0, 1}, ExactFunction)
+	assert.Error(t, err)
+	_, err = model.AddPiecewiseLinear(x, []float64{0, math.Inf(1)}, []float64{0, 1}, ExactFunction)
+	assert.Error(t, err)
+	_, err = model.AddPiecewiseLinear(x, []float64{0, 1}, []float64{0, 1}, MaximizedFunction+1)
+	assert.Error(t, err)
+
+	removed, _ := model.AddDefinedVariable("removed", ContinuousVariable, 0, 0, 1)
+	require.NoError(t, model.RemoveVariable(removed))
+	count := model.VariableCount()
+	_, err = model.AddPiecewiseLinear(removed, []float64{0, 1}, []float64{0, 1}, ExactFunction)
+	assert.Error(t, err)
+	assert.Equal(t, count, model.VariableCount())
+}
+
+func TestIndicatorConstraint(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		sense    ConstraintType
+		active   float64
+		expected float64
+	}{
+		{name: "<= active", sense: LessOrEqualConstraint, active: 1, expected: 4},
+		{name: "<= inactive", sense: LessOrEqualConstraint, active: 0, expected: 10},
+		{name: ">= active", sense: GreaterOrEqualConstraint, active: 1, expected: 10},
+		{name: "= active", sense: EqualConstraint, active: 1, expected: 4},
+	} {
+		model, err := NewModel("test", Maximize)
+		require.NoError(t, err)
+
+		x, _ := model.AddDefinedVariable("x", ContinuousVariable, 1, 0, 10)
+		b, _ := model.AddDefinedVariable("b", BinaryVariable, 0, 0, 1)
+		require.NoError(t, b.Fix(tc.active))
+
+		_, err = model.AddIndicatorConstraint(b, x.Times(2), tc.sense, 8)
+		require.NoError(t, err, tc.name)
+
+		res, err := model.Solve()
+		require.NoError(t, err, tc.name)
+		assert.InDelta(t, tc.expected, res.Value(x), delta, tc.name)
+	}
+
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 0, 0, math.Inf(1))
+	free, _ := model.AddVariable("free")
+	b, _ := model.AddDefinedVariable("b", BinaryVariable, 0, 0, 1)
+
+	// x has no upper bound
+	_, err = model.AddIndicatorConstraint(b, x.Expr(), LessOrEqualConstraint, 8)
+	assert.Error(t, err)
+	// x has a lower bound of 0
+	_, err = model.AddIndicatorConstraint(b, x.Expr(), GreaterOrEqualConstraint, 8)
+	assert.NoError(t, err)
+	_, err = model.AddIndicatorConstraint(b, free.Expr(), GreaterOrEqualConstraint, 8)
+	assert.Error(t, err)
+	_, err = model.AddIndicatorConstraint(x, b.Expr(), LessOrEqualConstraint, 0)
+	assert.Error(t, err)
+}
+
+func TestLogic(t *testing.T) {
+	for _, tc := range []struct {
+		a, b                   float64
+		and, or, notA, implies bool
+	}{
+		{a: 0, b: 0, and: false, or: false, notA: true, implies: true},
+		{a: 0, b: 1, and: false, or: true, notA: true, implies: true},
+		{a: 1, b: 0, and: false, or: true, notA: false, implies: false},
+		{a: 1, b: 1, and: true, or: true, notA: false, implies: true},
+	} {
+		model, err := NewModel("test", Minimize)
+		require.NoError(t, err)
+
+		a, _ := model.AddDefinedVariable("a", BinaryVariable, 0, 0, 1)
+		b, _ := model.AddDefinedVariable("b", BinaryVariable, 0, 0, 1)
+		require.NoError(t, a.Fix(tc.a))
+		require.NoError(t, b.Fix(tc.b))
+
+		and, err := model.And(a, b)
+		require.NoError(t, err)
+		or, err := model.Or(a, b)
+		require.NoError(t, err)
+		notA, err := model.Not(a)
+		require.NoError(t, err)
+
+		res, err := model.Solve()
+		require.NoError(t, err)
+		assert.Equal(t, tc.and, res.Value(and) > 0.5, "%v and %v", tc.a, tc.b)
+		assert.Equal(t, tc.or, res.Value(or) > 0.5, "%v or %v", tc.a, tc.b)
+		assert.Equal(t, tc.notA, res.Value(notA) > 0.5, "not %v", tc.a)
+
+		_, err = model.Implies(a, b)
+		require.NoError(t, err)
+
+		_, err = model.Solve()
+		if tc.implies {
+			assert.NoError(t, err, "%v implies %v", tc.a, tc.b)
+		} else {
+			assert.ErrorIs(t, err, ErrModelInfeasible, "%v implies %v", tc.a, tc.b)
+		}
+	}
+
+	model, err := NewModel("test", Minimize)
+	require.NoError(t, err)
+	x, _ := model.AddVariable("x")
+	b, _ := model.AddDefinedVariable("b", BinaryVariable, 0, 0, 1)
+
+	_, err = model.And()
+	assert.Error(t, err)
+	_, err = model.Or(b, x)
+	assert.Error(t, err)
+	_, err = model.Not(x)
+	assert.Error(t, err)
+}
+
+func TestAbsMinMax(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		dir          direction
+		op           string
+		use          FunctionUse
+		expected     float64
+		expectedVars int
+	}{
+		// minimizing |x| and max(x, y) and maximizing min(-x, 2y) need no
+		// binaries
+		{name: "min abs", dir: Minimize, op: "abs", use: MinimizedFunction, expected: 2, expectedVars: 3},
+		{name: "min abs exact", dir: Minimize, op: "abs", use: ExactFunction, expected: 2, expectedVars: 5},
+		{name: "max abs", dir: Maximize, op: "abs", use: MaximizedFunction, expected: 6, expectedVars: 5},
+		{name: "min max", dir: Minimize, op: "max", use: MinimizedFunction, expected: 1, expectedVars: 3},
+		{name: "max max", dir: Maximize, op: "max", use: MaximizedFunction, expected: 4, expectedVars: 5},
+		{name: "max min", dir: Maximize, op: "min", use: MaximizedFunction, expected: 6, expectedVars: 3},
+		{name: "min min", dir: Minimize, op: "min", use: MinimizedFunction, expected: 2, expectedVars: 5},
+	} {
+		model, err := NewModel("test", tc.dir)
+		require.NoError(t, err)
+
+		// x in [-6, -2], y in [1, 4]
+		x, _ := model.AddDefinedVariable("x", ContinuousVariable, 0, -6, -2)
+		y, _ := model.AddDefinedVariable("y", ContinuousVariable, 0, 1, 4)
+
+		var z *Variable
+		switch tc.op {
+		case "abs":
+			z, err = model.Abs(tc.use, x)
+		case "max":
+			z, err = model.Max(tc.use, x, y)
+		case "min":
+			z, err = model.Min(tc.use, x.Times(-1), y.Times(2))
+		}
+		require.NoError(t, err, tc.name)
+		assert.Len(t, model.Variables(), tc.expectedVars, tc.name)
+		assert.Equal(t, 0.0, z.Coefficient(), tc.name)
+		z.SetObjectiveCoefficient(1)
+
+		res, err := model.Solve()
+		require.NoError(t, err, tc.name)
+		assert.InDelta(t, tc.expected, res.Value(z), delta, tc.name)
+	}
+
+	// the maximum only used in a constraint: max(x, y) <= 3
+	model, err := NewModel("test", Maximize)
+	require.NoError(t, err)
+	x, _ := model.AddDefinedVariable("x", ContinuousVariable, 0, -6, -2)
+	y, _ := model.AddDefinedVariable("y", ContinuousVariable, 1, 1, 4)
+
+	z, err := model.Max(ExactFunction, x, y)
+	require.NoError(t, err)
+	_, err = model.AddConstraintExpr(z.Expr().LessEq(3))
+	require.NoError(t, err)
+
+	res, err := model.Solve()
+	require.NoError(t, err)
+	assert.InDelta(t, 3, res.ObjectiveValue(), delta)
+	assert.InDelta(t, 3, res.Value(z), delta)
+
+	// free has no bounds
+	free, _ := model.AddVariable("free")
+	_, err = model.Abs(ExactFunction, free)
+	assert.Error(t, err)
+	_, err = model.Abs(MinimizedFunction, free)
+	assert.NoError(t, err)
+	_, err = model.Max(ExactFunction)
+	assert.Error(t, err)
+	_, err = model.Min(MaximizedFunction+1, x)
+	assert.Error(t, err)
+
+	removed, _ := model.AddDefinedVariable("removed", ContinuousVariable, 0, 0, 1)
+	require.NoError(t, model.RemoveVariable(removed))
+	count := model.VariableCount()
+	_, err = model.Max(ExactFunction, x, removed)
+	assert.Error(t, err)
+	assert.Equal(t, count, model.VariableCount())
+}
+
 func TestBig(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping test in short mode.")
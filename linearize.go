@@ -0,0 +1,142 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"fmt"
+	"math"
+)
+
+// Abs adds a variable representing the absolute value of the given term,
+// with an objective coefficient of 0.
+//
+// If use is MinimizedFunction, the variable is only bounded from below by
+// term and -term, which keeps the model linear. Otherwise binary variables
+// select which of the two the variable equals, using big-M constraints
+// derived from the current bounds of the variables in term; an error is
+// returned if one of these bounds is infinite.
+func (model *Model) Abs(use FunctionUse, term Linear) (*Variable, error) {
+	e := term.Expr()
+
+	return model.extremum(1, use, []Expr{e, e.Times(-1)})
+}
+
+// Max adds a variable representing the largest of the given terms, with an
+// objective coefficient of 0.
+//
+// If use is MinimizedFunction, the variable is only bounded from below by
+// each term; otherwise it is made equal to the largest one as described
+// for Abs, which requires finite variable bounds.
+func (model *Model) Max(use FunctionUse, terms ...Linear) (*Variable, error) {
+	return model.extremum(1, use, linearsAsExprs(terms))
+}
+
+// Min adds a variable representing the smallest of the given terms, with
+// an objective coefficient of 0.
+//
+// If use is MaximizedFunction, the variable is only bounded from above by
+// each term; otherwise it is made equal to the smallest one as described
+// for Abs, which requires finite variable bounds.
+func (model *Model) Min(use FunctionUse, terms ...Linear) (*Variable, error) {
+	return model.extremum(-1, use, linearsAsExprs(terms))
+}
+
+// extremum adds a variable z representing the maximum (sign 1) or minimum
+// (sign -1) of the given expressions. Both cases are handled as the
+// maximum of sign * exprs.
+func (model *Model) extremum(sign float64, use FunctionUse, exprs []Expr) (*Variable, error) {
+	if err := use.validate(); err != nil {
+		return nil, err
+	}
+	if len(exprs) == 0 {
+		return nil, fmt.Errorf("no terms given")
+	}
+	for i, e := range exprs {
+		vars, _ := e.Terms()
+		for _, v := range vars {
+			if v.model != model || v.index < 0 {
+				return nil, fmt.Errorf("variable of term %d not part of model", i)
+			}
+		}
+	}
+
+	lowest := make([]float64, len(exprs))
+	highest := make([]float64, len(exprs))
+	maxLowest, maxHighest := math.Inf(-1), math.Inf(-1)
+	bounded := true
+	for i, e := range exprs {
+		lowest[i], highest[i] = e.Times(sign).bounds()
+		maxLowest = math.Max(maxLowest, lowest[i])
+		maxHighest = math.Max(maxHighest, highest[i])
+		bounded = bounded && !math.IsInf(lowest[i], -1) && !math.IsInf(highest[i], 1)
+	}
+
+	// bounding sign * z from below suffices if it is pushed down
+	relaxed := (sign > 0 && use == MinimizedFunction) || (sign < 0 && use == MaximizedFunction)
+	if !relaxed && !bounded {
+		return nil, fmt.Errorf("extremum needs finite variable bounds")
+	}
+
+	lower, upper := maxLowest, maxHighest
+	if sign < 0 {
+		lower, upper = -maxHighest, -maxLowest
+	}
+	z, err := model.AddDefinedVariable("", ContinuousVariable, 0, lower, upper)
+	if err != nil {
+		return nil, err
+	}
+
+	// sign * z >= sign * expr_i for all i
+	for _, e := range exprs {
+		if _, err := model.AddConstraintExpr(z.Times(sign).Minus(e.Times(sign)).GreaterEq(0)); err != nil {
+			return nil, err
+		}
+	}
+
+	if relaxed {
+		return z, nil
+	}
+
+	// sign * z <= sign * expr_i + M_i (1 - b_i) for all i, with exactly one
+	// b_i = 1 selecting the expression z is equal to
+	selectors := make([]*Variable, len(exprs))
+	for i, e := range exprs {
+		if selectors[i], err = model.AddDefinedVariable("", BinaryVariable, 0, 0, 1); err != nil {
+			return nil, err
+		}
+
+		bigM := maxHighest - lowest[i]
+		if _, err := model.AddConstraintExpr(z.Times(sign).Minus(e.Times(sign)).Plus(selectors[i].Times(bigM)).LessEq(bigM)); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := model.AddConstraintExpr(Sum(variablesAsLinear(selectors)...).Eq(1)); err != nil {
+		return nil, err
+	}
+
+	return z, nil
+}
+
+func linearsAsExprs(terms []Linear) []Expr {
+	exprs := make([]Expr, len(terms))
+	for i, t := range terms {
+		exprs[i] = t.Expr()
+	}
+
+	return exprs
+}
@@ -1,5 +1,10 @@
 package golpa
 
+import (
+	"log/slog"
+	"unsafe"
+)
+
 type Logger interface {
 	Print(v ...interface{})
 }
@@ -7,3 +12,13 @@ type Logger interface {
 type noopLogger struct{}
 
 func (noopLogger) Print(v ...interface{}) {}
+
+// logSink receives the log messages of an underlying problem.
+type logSink struct {
+	logger      Logger
+	slogHandler slog.Handler
+	// solving is set while the problem is being solved
+	solving bool
+	// ref is the reference passed to the log callback
+	ref unsafe.Pointer
+}
@@ -0,0 +1,215 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"fmt"
+	"math"
+)
+
+// AddIndicatorConstraint adds constraints enforcing "expr sense rhs"
+// whenever the binary variable b is 1, e.g. "if b then expr <= rhs".
+// sense must be LessOrEqualConstraint, GreaterOrEqualConstraint or
+// EqualConstraint; the latter adds two constraints.
+//
+// The constraints use a big-M formulation, with M computed from the
+// current bounds of the variables in expr. An error is returned if a
+// needed bound is infinite. Changing the bounds afterwards may invalidate
+// the constraints.
+func (model *Model) AddIndicatorConstraint(b *Variable, expr Expr, sense ConstraintType, rhs float64) ([]*Constraint, error) {
+	if err := model.checkBinary(b); err != nil {
+		return nil, err
+	}
+
+	vars, _ := expr.Terms()
+	for i, v := range vars {
+		if v.model != model {
+			return nil, fmt.Errorf("variable %d not part of model", i)
+		}
+	}
+
+	lowest, highest := expr.bounds()
+
+	var rels []Relation
+	switch sense {
+	case LessOrEqualConstraint:
+		rels = append(rels, indicatorLessEq(b, expr, rhs, highest))
+	case GreaterOrEqualConstraint:
+		rels = append(rels, indicatorGreaterEq(b, expr, rhs, lowest))
+	case EqualConstraint:
+		rels = append(rels, indicatorLessEq(b, expr, rhs, highest), indicatorGreaterEq(b, expr, rhs, lowest))
+	default:
+		return nil, fmt.Errorf("unsupported indicator constraint type: %d", sense)
+	}
+
+	if (sense != GreaterOrEqualConstraint && math.IsInf(highest, 1)) || (sense != LessOrEqualConstraint && math.IsInf(lowest, -1)) {
+		return nil, fmt.Errorf("indicator constraint needs finite variable bounds")
+	}
+
+	constraints := make([]*Constraint, 0, len(rels))
+	for _, r := range rels {
+		c, err := model.AddConstraintExpr(r)
+		if err != nil {
+			return nil, err
+		}
+		constraints = append(constraints, c)
+	}
+
+	return constraints, nil
+}
+
+// indicatorLessEq returns "expr + M b <= rhs + M", with M just large
+// enough for the relation to hold for b = 0.
+func indicatorLessEq(b *Variable, expr Expr, rhs, highest float64) Relation {
+	bigM := math.Max(highest-rhs, 0)
+
+	return expr.Plus(b.Times(bigM)).LessEq(rhs + bigM)
+}
+
+// indicatorGreaterEq returns "expr - M b >= rhs - M", with M just large
+// enough for the relation to hold for b = 0.
+func indicatorGreaterEq(b *Variable, expr Expr, rhs, lowest float64) Relation {
+	bigM := math.Max(rhs-lowest, 0)
+
+	return expr.Minus(b.Times(bigM)).GreaterEq(rhs - bigM)
+}
+
+// bounds returns the smallest and largest values the expression can take,
+// given the current bounds of its variables.
+func (e Expr) bounds() (lowest, highest float64) {
+	lowest, highest = e.constant, e.constant
+
+	vars, coefs := e.Terms()
+	for i, v := range vars {
+		lower, upper := v.Bounds()
+		switch {
+		case coefs[i] > 0:
+			lowest += coefs[i] * lower
+			highest += coefs[i] * upper
+		case coefs[i] < 0:
+			lowest += coefs[i] * upper
+			highest += coefs[i] * lower
+		}
+	}
+
+	return lowest, highest
+}
+
+// Implies adds the constraint "if a then b" over the binary variables a
+// and b.
+func (model *Model) Implies(a, b *Variable) (*Constraint, error) {
+	if err := model.checkBinary(a, b); err != nil {
+		return nil, err
+	}
+
+	return model.AddConstraintExpr(a.Expr().Minus(b).LessEq(0))
+}
+
+// And returns a new binary variable that is 1 if and only if all of the
+// given binary variables are 1. The new variable has an objective
+// coefficient of 0.
+func (model *Model) And(vars ...*Variable) (*Variable, error) {
+	if len(vars) == 0 {
+		return nil, fmt.Errorf("no variables given")
+	}
+	if err := model.checkBinary(vars...); err != nil {
+		return nil, err
+	}
+
+	z, err := model.AddDefinedVariable("", BinaryVariable, 0, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	// z <= v for all v, z >= sum(v) - (n - 1)
+	for _, v := range vars {
+		if _, err := model.AddConstraintExpr(z.Expr().Minus(v).LessEq(0)); err != nil {
+			return nil, err
+		}
+	}
+	sum := Sum(variablesAsLinear(vars)...)
+	if _, err := model.AddConstraintExpr(z.Expr().Minus(sum).GreaterEq(1 - float64(len(vars)))); err != nil {
+		return nil, err
+	}
+
+	return z, nil
+}
+
+// Or returns a new binary variable that is 1 if and only if at least one
+// of the given binary variables is 1. The new variable has an objective
+// coefficient of 0.
+func (model *Model) Or(vars ...*Variable) (*Variable, error) {
+	if len(vars) == 0 {
+		return nil, fmt.Errorf("no variables given")
+	}
+	if err := model.checkBinary(vars...); err != nil {
+		return nil, err
+	}
+
+	z, err := model.AddDefinedVariable("", BinaryVariable, 0, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	// z >= v for all v, z <= sum(v)
+	for _, v := range vars {
+		if _, err := model.AddConstraintExpr(z.Expr().Minus(v).GreaterEq(0)); err != nil {
+			return nil, err
+		}
+	}
+	sum := Sum(variablesAsLinear(vars)...)
+	if _, err := model.AddConstraintExpr(z.Expr().Minus(sum).LessEq(0)); err != nil {
+		return nil, err
+	}
+
+	return z, nil
+}
+
+// Not returns a new binary variable that is 1 if and only if the given
+// binary variable is 0. The new variable has an objective coefficient of 0.
+func (model *Model) Not(v *Variable) (*Variable, error) {
+	if err := model.checkBinary(v); err != nil {
+		return nil, err
+	}
+
+	z, err := model.AddDefinedVariable("", BinaryVariable, 0, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := model.AddConstraintExpr(z.Expr().Plus(v).Eq(1)); err != nil {
+		return nil, err
+	}
+
+	return z, nil
+}
+
+// checkBinary returns an error if any of the given variables is not a
+// binary variable of the model.
+func (model *Model) checkBinary(vars ...*Variable) error {
+	for i, v := range vars {
+		if v.model != model {
+			return fmt.Errorf("variable %d not part of model", i)
+		}
+		if v.Type() != BinaryVariable {
+			return fmt.Errorf("variable %d (%s) is not binary", i, v.Name())
+		}
+	}
+
+	return nil
+}
@@ -1,5 +1,7 @@
 package golpa
 
+import "log/slog"
+
 type Option func(*Model) error
 
 func WithLogger(logger Logger) Option {
@@ -9,3 +11,70 @@ func WithLogger(logger Logger) Option {
 		return nil
 	}
 }
+
+// WithSensitivityAnalysis enables sensitivity analysis while solving the
+// model, which makes SolveResult.Sensitivity available. This is also
+// needed for dual values of models with integer variables.
+func WithSensitivityAnalysis() Option {
+	return func(m *Model) error {
+		m.sensitivity = true
+
+		return nil
+	}
+}
+
+// WithSolverOptions sets the parameters of the underlying solver.
+func WithSolverOptions(opts SolverOptions) Option {
+	return func(m *Model) error {
+		if err := opts.validate(); err != nil {
+			return err
+		}
+
+		m.setSolverOptions(opts)
+
+		return nil
+	}
+}
+
+// WithImprovedSolutionCallback registers a function to be called each time
+// the branch-and-bound search of a model with integer variables finds an
+// improved solution. The function is called synchronously from within
+// Solve, which waits for it to return. Since Solve works on a copy of the
+// model, the function may use the model, but changes to it only affect
+// later solves. To accept a solution that is good enough, cancel the
+// context passed to SolveWithContext; the result will then have status
+// SolutionSuboptimal.
+func WithImprovedSolutionCallback(fn func(ImprovedSolution)) Option {
+	return func(m *Model) error {
+		m.improvedSolutionCallback = fn
+
+		return nil
+	}
+}
+
+// WithSlogHandler sends the messages of the underlying solver to the given
+// structured logging handler instead of the Logger set with WithLogger.
+// Since the solver does not report the level of individual messages, all
+// records are logged at slog.LevelInfo; use WithVerbosity to select which
+// messages are logged. Each record carries the model name, the phase
+// ("model" or "solve") and the verbosity level as attributes.
+func WithSlogHandler(h slog.Handler) Option {
+	return func(m *Model) error {
+		m.slogHandler = h
+
+		return nil
+	}
+}
+
+// WithVerbosity sets which messages are logged by the underlying solver.
+func WithVerbosity(v Verbosity) Option {
+	return func(m *Model) error {
+		if err := v.validate(); err != nil {
+			return err
+		}
+
+		m.setVerbosity(v)
+
+		return nil
+	}
+}
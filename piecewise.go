@@ -0,0 +1,159 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"fmt"
+	"math"
+)
+
+// FunctionUse states how the variable representing a function, as added
+// by e.g. AddPiecewiseLinear, is used by the model. Knowing that the
+// optimization pushes the variable towards the function's value allows for
+// cheaper formulations.
+type FunctionUse int
+
+const (
+	// ExactFunction makes no assumptions: the variable always equals the
+	// function's value.
+	ExactFunction FunctionUse = iota
+	// MinimizedFunction states that the optimization pushes the variable
+	// down, e.g. because it has a positive objective coefficient in a
+	// minimizing model. The variable is then only bounded from below by the
+	// function's value, and equals it in optimal solutions.
+	MinimizedFunction
+	// MaximizedFunction states that the optimization pushes the variable
+	// up, e.g. because it has a positive objective coefficient in a
+	// maximizing model. The variable is then only bounded from above by the
+	// function's value, and equals it in optimal solutions.
+	MaximizedFunction
+)
+
+func (use FunctionUse) validate() error {
+	if use < ExactFunction || use > MaximizedFunction {
+		return fmt.Errorf("invalid function use: %d", int(use))
+	}
+
+	return nil
+}
+
+// AddPiecewiseLinear adds a variable representing f(x), where f is the
+// piecewise linear function through the points (breakpoints[i], values[i]).
+// The breakpoints must be strictly increasing, and x is restricted to lie
+// between the first and the last of them. The new variable has an
+// objective coefficient of 0.
+//
+// f is modeled as a convex combination of the points, with an SOS2 set
+// ensuring that only adjacent points are combined. If f is convex and use
+// is MinimizedFunction, or f is concave and use is MaximizedFunction, the
+// SOS2 set is omitted, keeping the model linear.
+func (model *Model) AddPiecewiseLinear(x *Variable, breakpoints, values []float64, use FunctionUse) (*Variable, error) {
+	if err := use.validate(); err != nil {
+		return nil, err
+	}
+	if len(breakpoints) != len(values) {
+		return nil, fmt.Errorf("inconsistent number of breakpoints and values: %d != %d", len(breakpoints), len(values))
+	}
+	if len(breakpoints) < 2 {
+		return nil, fmt.Errorf("at least 2 breakpoints needed, got %d", len(breakpoints))
+	}
+	for i := range breakpoints {
+		if math.IsNaN(breakpoints[i]) || math.IsInf(breakpoints[i], 0) || math.IsNaN(values[i]) || math.IsInf(values[i], 0) {
+			return nil, fmt.Errorf("breakpoint %d is not finite", i)
+		}
+		if i > 0 && breakpoints[i] <= breakpoints[i-1] {
+			return nil, fmt.Errorf("breakpoints not strictly increasing at %d", i)
+		}
+	}
+	if x.model != model || x.index < 0 {
+		return nil, fmt.Errorf("variable not part of model")
+	}
+
+	y, err := model.AddDefinedVariable("", ContinuousVariable, 0, math.Inf(-1), math.Inf(1))
+	if err != nil {
+		return nil, err
+	}
+
+	// x = sum(lambda_i * breakpoint_i), y = sum(lambda_i * value_i) and
+	// sum(lambda_i) = 1, with all lambda_i in [0, 1]
+	lambdas := make([]*Variable, len(breakpoints))
+	for i := range lambdas {
+		if lambdas[i], err = model.AddDefinedVariable("", ContinuousVariable, 0, 0, 1); err != nil {
+			return nil, err
+		}
+	}
+
+	xExpr := x.Times(-1)
+	yExpr := y.Times(-1)
+	for i, lambda := range lambdas {
+		xExpr = xExpr.Plus(lambda.Times(breakpoints[i]))
+		yExpr = yExpr.Plus(lambda.Times(values[i]))
+	}
+
+	if _, err := model.AddConstraintExpr(xExpr.Eq(0)); err != nil {
+		return nil, err
+	}
+	if _, err := model.AddConstraintExpr(yExpr.Eq(0)); err != nil {
+		return nil, err
+	}
+	if _, err := model.AddConstraintExpr(Sum(variablesAsLinear(lambdas)...).Eq(1)); err != nil {
+		return nil, err
+	}
+
+	convex, concave := convexity(breakpoints, values)
+	if (convex && use == MinimizedFunction) || (concave && use == MaximizedFunction) {
+		return y, nil
+	}
+
+	if err := model.AddSOS(SOS2, 1, lambdas, breakpoints); err != nil {
+		return nil, err
+	}
+
+	return y, nil
+}
+
+// convexity reports whether the piecewise linear function through the
+// given points is convex and/or concave. Linear functions are both.
+func convexity(breakpoints, values []float64) (convex, concave bool) {
+	convex, concave = true, true
+
+	prevSlope := math.NaN()
+	for i := 1; i < len(breakpoints); i++ {
+		slope := (values[i] - values[i-1]) / (breakpoints[i] - breakpoints[i-1])
+		if i > 1 {
+			if slope < prevSlope {
+				convex = false
+			}
+			if slope > prevSlope {
+				concave = false
+			}
+		}
+		prevSlope = slope
+	}
+
+	return convex, concave
+}
+
+func variablesAsLinear(vars []*Variable) []Linear {
+	terms := make([]Linear, len(vars))
+	for i, v := range vars {
+		terms[i] = v
+	}
+
+	return terms
+}
@@ -0,0 +1,121 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// SolvePortfolio solves the model concurrently once for each of the given
+// solver options and returns the first optimal result, aborting the
+// remaining solves. If no solve finds an optimal solution, e.g. because
+// the context expired, the best suboptimal result is returned instead.
+// If no solve succeeds, the errors of all solves are returned. The results
+// not returned are closed.
+func SolvePortfolio(ctx context.Context, model *Model, opts []SolverOptions) (*SolveResult, error) {
+	if len(opts) == 0 {
+		return nil, fmt.Errorf("no solver options given")
+	}
+	for i, o := range opts {
+		if err := o.validate(); err != nil {
+			return nil, fmt.Errorf("solver options %d: %w", i, err)
+		}
+	}
+
+	model.mu.RLock()
+	err := model.checkOpen()
+	model.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	dir := model.Direction()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type outcome struct {
+		res *SolveResult
+		err error
+	}
+
+	outcomes := make(chan outcome, len(opts))
+	for i := range opts {
+		o := opts[i]
+		go func() {
+			res, err := model.solve(ctx, &o)
+			outcomes <- outcome{res, err}
+		}()
+	}
+
+	var (
+		best *SolveResult
+		errs []error
+	)
+	for i := range opts {
+		o := <-outcomes
+		if o.err != nil {
+			errs = append(errs, o.err)
+			continue
+		}
+
+		if o.res.Status() == SolutionOptimal {
+			// the remaining solves notice the cancellation through their
+			// abort callbacks and are left to finish in the background
+			cancel()
+			if best != nil {
+				best.Close()
+			}
+			go func(remaining int) {
+				for ; remaining > 0; remaining-- {
+					if o := <-outcomes; o.err == nil {
+						o.res.Close()
+					}
+				}
+			}(len(opts) - i - 1)
+			return o.res, nil
+		}
+
+		if best == nil || better(dir, o.res.ObjectiveValue(), best.ObjectiveValue()) {
+			if best != nil {
+				best.Close()
+			}
+			best = o.res
+		} else {
+			o.res.Close()
+		}
+	}
+
+	if best != nil {
+		return best, nil
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// better reports whether objective value a is better than b in the given
+// optimization direction.
+func better(dir direction, a, b float64) bool {
+	if dir == Maximize {
+		return a > b
+	}
+
+	return a < b
+}
@@ -23,11 +23,62 @@ package golpa
 // #include <stdlib.h>
 import "C"
 
+import (
+	"errors"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+	"unsafe"
+)
+
 /* Types */
 
 type SolveResult struct {
 	model  *Model
 	status SolveStatus
+	stats  SolveStats
+	sol    *solution
+}
+
+// solution is the snapshot of a model that was solved, owned by a
+// SolveResult.
+type solution struct {
+	mu        sync.Mutex
+	prob      *C.lprec
+	log       *logSink
+	presolved bool
+	// vars and constraints map the model's handles to their indices at the
+	// time of the snapshot
+	vars        map[*Variable]int
+	constraints map[*Constraint]int
+	// bounds holds the constraint bounds of presolved snapshots, since
+	// presolve may remove or change rows
+	bounds [][2]float64
+}
+
+// SolveStats holds statistics about the solve that produced a result.
+type SolveStats struct {
+	// Iterations is the total number of simplex iterations.
+	Iterations int64
+	// Nodes is the number of branch-and-bound nodes explored.
+	Nodes int64
+	// MaxDepth is the deepest branch-and-bound level reached.
+	MaxDepth int
+	// Elapsed is the wall time spent solving.
+	Elapsed time.Duration
+	// RootGapAbsolute is the difference between the objective value and
+	// the objective value of the root LP relaxation, which bounds all
+	// integer solutions. It is 0 for models without integer variables.
+	// Since the bound is not tightened during branch-and-bound, this is
+	// not the gap checked against SolverOptions.MIPGapAbsolute, and may be
+	// non-zero even for proven optimal solutions.
+	RootGapAbsolute float64
+	// RootGapRelative is RootGapAbsolute divided by 1+|objective value|.
+	RootGapRelative float64
+	// ImprovedSolutions is the number of improved solutions found by
+	// branch-and-bound.
+	ImprovedSolutions int
 }
 
 type SolveStatus C.int
@@ -49,7 +100,7 @@ const (
 	ErrNoFeasibleFound  = SolveError(C.NOFEASFOUND)
 	ErrNoMemory         = SolveError(C.NOMEMORY)
 	ErrNumericalFailure = SolveError(C.NUMFAILURE)
-	ErrPresolved        = SolveError(C.PRESOLVED) // should not be seen: we can't use C.set_presolve because it might remove variables behind our backs
+	ErrPresolved        = SolveError(C.PRESOLVED)
 	ErrTimeout          = SolveError(C.TIMEOUT)
 	ErrUserAbort        = SolveError(C.USERABORT)
 )
@@ -76,7 +127,7 @@ func (e SolveError) Error() string {
 	case ErrNumericalFailure:
 		return "numerical failure while solving"
 	case ErrPresolved:
-		return "model was presolved"
+		return "model was fully handled by presolve"
 	case ErrTimeout:
 		return "timeout occurred before any integer solution could be found"
 	case ErrUserAbort:
@@ -92,6 +143,11 @@ func (res SolveResult) Status() SolveStatus {
 	return res.status
 }
 
+// Stats returns statistics about the solve that produced this result.
+func (res SolveResult) Stats() SolveStats {
+	return res.stats
+}
+
 // Value returns the computed value of the given variable for this
 // optimization result.
 // This is a shorthand for PrimalValue.
@@ -100,31 +156,232 @@ func (res SolveResult) Value(v *Variable) float64 {
 }
 
 // PrimalValue returns the computed value of the given variable for
-// this optimization result.
+// this optimization result. Variables added to the model after solving
+// have a value of NaN.
 func (res SolveResult) PrimalValue(v *Variable) float64 {
-	res.model.mu.RLock()
-	defer res.model.mu.RUnlock()
+	index, ok := res.sol.vars[v]
+	if !ok {
+		return math.NaN()
+	}
 
-	// get_var_*result uses funny indexing: 0=objective,1 to Nrows=constraint,Nrows to Nrows+Ncols=variable
-	return float64(C.get_var_primalresult(res.model.prob, C.int(v.index+v.model.ConstraintCount()+1)))
+	prob, unlock := res.mustLock()
+	defer unlock()
+
+	// get_var_primalresult uses funny indexing: 0=objective,1 to Nrows=constraint,Nrows to Nrows+Ncols=variable
+	// Since it reports the full solution, the indices are those of the original problem, even after presolve.
+	return float64(C.get_var_primalresult(prob, C.int(index+int(C.get_Norig_rows(prob))+1)))
 }
 
 // DualValue returns the dual value of the given variable in this
 // optimization result.
+// Variables eliminated by presolve have a dual value of 0, variables added
+// to the model after solving have a dual value of NaN.
 func (res SolveResult) DualValue(v *Variable) float64 {
-	res.model.mu.RLock()
-	defer res.model.mu.RUnlock()
+	index, ok := res.sol.vars[v]
+	if !ok {
+		return math.NaN()
+	}
+
+	prob, unlock := res.mustLock()
+	defer unlock()
 
-	// get_var_*result uses funny indexing: 0=objective,1 to Nrows=constraint,Nrows to Nrows+Ncols=variable
-	return float64(C.get_var_dualresult(res.model.prob, C.int(v.index+v.model.ConstraintCount()+1)))
+	return res.dualValue(prob, index+int(C.get_Norig_rows(prob))+1)
 }
 
 // ObjectiveValue returns the value of the objective function for
 // this optimization result. This value is only optimal if Status
 // also returns SolutionOptimal.
 func (res SolveResult) ObjectiveValue() float64 {
-	res.model.mu.RLock()
-	defer res.model.mu.RUnlock()
+	prob, unlock := res.mustLock()
+	defer unlock()
+
+	return float64(C.get_objective(prob))
+}
+
+// ConstraintActivity returns the value of the given constraint's
+// expression for this optimization result. Constraints added to the model
+// after solving have an activity of NaN.
+func (res SolveResult) ConstraintActivity(c *Constraint) float64 {
+	index, ok := res.sol.constraints[c]
+	if !ok {
+		return math.NaN()
+	}
+
+	prob, unlock := res.mustLock()
+	defer unlock()
+
+	return float64(C.get_var_primalresult(prob, C.int(index+1)))
+}
+
+// ConstraintDual returns the dual value (shadow price) of the given
+// constraint in this optimization result, i.e. the marginal change of
+// the objective value per unit change of the constraint's bound.
+// Constraints eliminated by presolve have a dual value of 0, constraints
+// added to the model after solving have a dual value of NaN.
+func (res SolveResult) ConstraintDual(c *Constraint) float64 {
+	index, ok := res.sol.constraints[c]
+	if !ok {
+		return math.NaN()
+	}
+
+	prob, unlock := res.mustLock()
+	defer unlock()
+
+	return res.dualValue(prob, index+1)
+}
+
+// Slack returns the distance between the given constraint's activity
+// and its nearest bound in this optimization result, using the bounds the
+// constraint had when solving. Constraints without bounds have an infinite
+// slack.
+func (res SolveResult) Slack(c *Constraint) float64 {
+	index, ok := res.sol.constraints[c]
+	if !ok {
+		return math.NaN()
+	}
+
+	prob, unlock := res.mustLock()
+	defer unlock()
+
+	var lower, upper float64
+	if res.sol.presolved {
+		lower, upper = res.sol.bounds[index][0], res.sol.bounds[index][1]
+	} else {
+		lower, upper = rowBounds(prob, index+1)
+	}
+	activity := float64(C.get_var_primalresult(prob, C.int(index+1)))
+
+	return math.Min(activity-lower, upper-activity)
+}
+
+// lock locks the underlying problem holding the solution and returns it,
+// along with the function to unlock it, or ErrResultClosed.
+func (res SolveResult) lock() (*C.lprec, func(), error) {
+	res.sol.mu.Lock()
+	if res.sol.prob == nil {
+		res.sol.mu.Unlock()
+		return nil, nil, ErrResultClosed
+	}
+
+	return res.sol.prob, res.sol.mu.Unlock, nil
+}
+
+// mustLock is like lock, but panics, for methods that cannot return
+// errors.
+func (res SolveResult) mustLock() (*C.lprec, func()) {
+	prob, unlock, err := res.lock()
+	if err != nil {
+		panic(err)
+	}
+
+	return prob, unlock
+}
+
+// lpIndex maps an index of the original problem (rows followed by columns,
+// starting at 1) to the corresponding index of the solved problem, which
+// differ if presolve removed rows or columns. Removed rows or columns are
+// mapped to 0.
+func (res SolveResult) lpIndex(prob *C.lprec, origIndex int) int {
+	if !res.sol.presolved {
+		return origIndex
+	}
+
+	// get_lp_index returns row numbers for rows, but column numbers for
+	// columns
+	index := int(C.get_lp_index(prob, C.int(origIndex)))
+	if index <= 0 {
+		return 0
+	}
+	if origIndex > int(C.get_Norig_rows(prob)) {
+		index += int(C.get_Nrows(prob))
+	}
+
+	return index
+}
+
+func (res SolveResult) dualValue(prob *C.lprec, origIndex int) float64 {
+	index := res.lpIndex(prob, origIndex)
+	if index == 0 {
+		return 0
+	}
+
+	// the duals are given for the (possibly reduced) solved problem, as
+	// rows followed by columns
+	var duals *C.REAL
+	if C.get_ptr_sensitivity_rhs(prob, &duals, nil, nil) != C.TRUE {
+		return 0
+	}
+
+	n := int(C.get_Nrows(prob) + C.get_Ncolumns(prob))
+
+	return float64(unsafe.Slice(duals, n)[index-1])
+}
+
+func solveStats(prob *C.lprec, elapsed time.Duration, improvedSolutions int) SolveStats {
+	stats := SolveStats{
+		Iterations:        int64(C.get_total_iter(prob)),
+		Nodes:             int64(C.get_total_nodes(prob)),
+		MaxDepth:          int(C.get_max_level(prob)),
+		Elapsed:           elapsed,
+		ImprovedSolutions: improvedSolutions,
+	}
+
+	objective := float64(C.get_objective(prob))
+	bound := rootBound(prob)
+	if math.Abs(bound) >= float64(C.get_infinite(prob)) {
+		// no relaxation was solved, so there is no bound
+		stats.RootGapAbsolute = math.Inf(1)
+		stats.RootGapRelative = math.Inf(1)
+		return stats
+	}
+
+	stats.RootGapAbsolute = math.Abs(objective - bound)
+	stats.RootGapRelative = stats.RootGapAbsolute / (1 + math.Abs(objective))
+
+	return stats
+}
+
+// rootBound returns the objective value of the root LP relaxation. Unlike
+// everything else, it is not available through a function of the
+// underlying library, so it is read from the problem struct declared in
+// lp_lib.h.
+func rootBound(prob *C.lprec) float64 {
+	return float64(prob.real_solution)
+}
+
+// ErrResultClosed is returned when using a result after calling Close.
+var ErrResultClosed = errors.New("result is closed")
+
+// Close frees the copy of the model held by this result immediately,
+// instead of waiting for the result to be garbage-collected. Since the
+// memory of the underlying library is invisible to the garbage collector,
+// results should be closed as soon as they are no longer needed,
+// especially when solving many times.
+// Afterwards, only Status and Stats remain usable; other methods return
+// ErrResultClosed, or panic with it if they cannot return errors. Closing
+// a closed result has no effect.
+func (res SolveResult) Close() error {
+	res.sol.mu.Lock()
+	defer res.sol.mu.Unlock()
+
+	res.sol.free()
+	runtime.SetFinalizer(res.sol, nil)
+
+	return nil
+}
+
+// free deletes the solution's problem, unless already done. The
+// solution's lock must be held once it is part of a result.
+func (sol *solution) free() {
+	if sol.prob == nil {
+		return
+	}
+
+	C.delete_lp(sol.prob)
+	releaseRef(sol.log.ref)
+	sol.prob = nil
+}
 
-	return float64(C.get_objective(res.model.prob))
+func finalizeSolution(sol *solution) {
+	sol.free()
 }
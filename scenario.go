@@ -0,0 +1,165 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #cgo CFLAGS: -I/usr/include/lpsolve/
+// #cgo LDFLAGS: -llpsolve55 -lm -ldl -lcolamd
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Scenario is a variant of a model, given as changes to the model's
+// variables and constraints. The changes only apply to the scenario's
+// solve; the model itself is not changed.
+type Scenario struct {
+	// Name identifies the scenario's result and must be unique.
+	Name string
+
+	// VariableBounds replaces the bounds of the given variables.
+	VariableBounds map[*Variable]Bounds
+	// ConstraintBounds replaces the bounds (i.e. the right-hand sides) of
+	// the given constraints.
+	ConstraintBounds map[*Constraint]Bounds
+	// ObjectiveCoefficients replaces the objective coefficients of the
+	// given variables.
+	ObjectiveCoefficients map[*Variable]float64
+}
+
+// Bounds is a pair of lower and upper bounds. Infinite bounds are given as
+// math.Inf(-1) and math.Inf(1).
+type Bounds struct {
+	Lower, Upper float64
+}
+
+// ScenarioResult is the outcome of solving a single scenario. Values can
+// be queried from Result using the variables and constraints of the
+// scenarios' model.
+type ScenarioResult struct {
+	Result *SolveResult
+	Err    error
+}
+
+// SolveScenarios solves each of the given scenarios of the model and
+// returns their outcomes keyed by scenario name. Up to parallelism
+// scenarios are solved concurrently; values below 2 solve them one after
+// the other. If the context is cancelled, the scenarios not solved yet
+// fail with the context's error. Each result holds a copy of the model
+// until it is closed with SolveResult.Close.
+func (model *Model) SolveScenarios(ctx context.Context, scenarios []Scenario, parallelism int) (map[string]ScenarioResult, error) {
+	names := make(map[string]bool, len(scenarios))
+	for _, s := range scenarios {
+		if names[s.Name] {
+			return nil, fmt.Errorf("duplicate scenario name: %q", s.Name)
+		}
+		names[s.Name] = true
+	}
+
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	var (
+		mu      sync.Mutex
+		results = make(map[string]ScenarioResult, len(scenarios))
+		wg      sync.WaitGroup
+		slots   = make(chan struct{}, parallelism)
+	)
+
+	for i := range scenarios {
+		s := &scenarios[i]
+
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			res, err := model.solveScenario(ctx, s)
+
+			mu.Lock()
+			results[s.Name] = ScenarioResult{Result: res, Err: err}
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	return results, nil
+}
+
+func (model *Model) solveScenario(ctx context.Context, s *Scenario) (*SolveResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sol, err := model.snapshot(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apply(sol); err != nil {
+		sol.free()
+		return nil, fmt.Errorf("scenario %q: %w", s.Name, err)
+	}
+
+	return model.solveSnapshot(ctx, sol)
+}
+
+// apply changes the given snapshot according to the scenario.
+func (s *Scenario) apply(sol *solution) error {
+	for v, b := range s.VariableBounds {
+		index, ok := sol.vars[v]
+		if !ok {
+			return fmt.Errorf("variable not part of model")
+		}
+		if err := checkBounds(b.Lower, b.Upper); err != nil {
+			return err
+		}
+		setColBounds(sol.prob, index+1, b.Lower, b.Upper)
+	}
+
+	for c, b := range s.ConstraintBounds {
+		index, ok := sol.constraints[c]
+		if !ok {
+			return fmt.Errorf("constraint not part of model")
+		}
+		if err := checkBounds(b.Lower, b.Upper); err != nil {
+			return err
+		}
+		setRowBounds(sol.prob, index+1, b.Lower, b.Upper)
+	}
+
+	for v, coef := range s.ObjectiveCoefficients {
+		index, ok := sol.vars[v]
+		if !ok {
+			return fmt.Errorf("variable not part of model")
+		}
+		// row 0 is the objective function
+		C.set_mat(sol.prob, 0, C.int(index+1), C.REAL(coef))
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #cgo CFLAGS: -I/usr/include/lpsolve/
+// #cgo LDFLAGS: -llpsolve55 -lm -ldl -lcolamd
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrNoSensitivity is returned when requesting the sensitivity analysis of
+// a model created without WithSensitivityAnalysis.
+var ErrNoSensitivity = errors.New("sensitivity analysis not enabled for model")
+
+// Sensitivity holds the sensitivity analysis of an optimization result.
+type Sensitivity struct {
+	// Objective holds the ranging of each variable's objective
+	// coefficient.
+	Objective map[*Variable]ObjectiveRange
+	// Variables holds the reduced cost of each variable.
+	Variables map[*Variable]DualRange
+	// Constraints holds the dual value (shadow price) of each constraint,
+	// along with the range of right-hand side values it is valid for.
+	Constraints map[*Constraint]DualRange
+}
+
+// ObjectiveRange describes how far a variable's objective coefficient may
+// move before the optimal basis changes.
+type ObjectiveRange struct {
+	// Lower and Upper limit the values the objective coefficient may take
+	// without changing the optimal basis.
+	Lower, Upper float64
+	// LowerObjectiveValue is the objective function value when the
+	// coefficient is at Lower. It is only finite for non-basic variables.
+	LowerObjectiveValue float64
+}
+
+// DualRange is a dual value together with the limits it is valid for.
+type DualRange struct {
+	Dual float64
+	// Lower and Upper are the limits ("from" and "till" values) within
+	// which Dual stays valid.
+	Lower, Upper float64
+}
+
+// Sensitivity returns the sensitivity analysis of this optimization
+// result. The model must have been created with WithSensitivityAnalysis.
+// Variables and constraints eliminated by presolve are not included; the
+// duals of presolved models additionally require PresolveSensDuals.
+func (res SolveResult) Sensitivity() (*Sensitivity, error) {
+	res.model.mu.RLock()
+	sensitivity := res.model.sensitivity
+	res.model.mu.RUnlock()
+
+	if !sensitivity {
+		return nil, ErrNoSensitivity
+	}
+
+	prob, unlock, err := res.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	nrows := int(C.get_Nrows(prob))
+	ncols := int(C.get_Ncolumns(prob))
+	norigRows := int(C.get_Norig_rows(prob))
+
+	// the extra element avoids taking the address of empty slices
+	objFrom := make([]C.REAL, ncols+1)
+	objTill := make([]C.REAL, ncols+1)
+	objFromValue := make([]C.REAL, ncols+1)
+	if C.get_sensitivity_objex(prob, &objFrom[0], &objTill[0], &objFromValue[0]) != C.TRUE {
+		return nil, fmt.Errorf("could not get objective sensitivity")
+	}
+
+	// the dual arrays hold all constraints followed by all variables
+	duals := make([]C.REAL, nrows+ncols+1)
+	dualsFrom := make([]C.REAL, nrows+ncols+1)
+	dualsTill := make([]C.REAL, nrows+ncols+1)
+	if C.get_sensitivity_rhs(prob, &duals[0], &dualsFrom[0], &dualsTill[0]) != C.TRUE {
+		return nil, fmt.Errorf("could not get right-hand side sensitivity")
+	}
+
+	inf := float64(C.get_infinite(prob))
+
+	sens := &Sensitivity{
+		Objective:   make(map[*Variable]ObjectiveRange, len(res.sol.vars)),
+		Variables:   make(map[*Variable]DualRange, len(res.sol.vars)),
+		Constraints: make(map[*Constraint]DualRange, len(res.sol.constraints)),
+	}
+
+	for v, index := range res.sol.vars {
+		i := res.lpIndex(prob, norigRows+index+1) - 1
+		if i < 0 {
+			continue
+		}
+
+		sens.Objective[v] = ObjectiveRange{
+			Lower:               fromLPValue(float64(objFrom[i-nrows]), inf),
+			Upper:               fromLPValue(float64(objTill[i-nrows]), inf),
+			LowerObjectiveValue: fromLPValue(float64(objFromValue[i-nrows]), inf),
+		}
+		sens.Variables[v] = DualRange{
+			Dual:  float64(duals[i]),
+			Lower: fromLPValue(float64(dualsFrom[i]), inf),
+			Upper: fromLPValue(float64(dualsTill[i]), inf),
+		}
+	}
+
+	for c, index := range res.sol.constraints {
+		i := res.lpIndex(prob, index+1) - 1
+		if i < 0 {
+			continue
+		}
+
+		sens.Constraints[c] = DualRange{
+			Dual:  float64(duals[i]),
+			Lower: fromLPValue(float64(dualsFrom[i]), inf),
+			Upper: fromLPValue(float64(dualsTill[i]), inf),
+		}
+	}
+
+	return sens, nil
+}
+
+// fromLPValue converts lp_solve's representation of infinity to the
+// corresponding Go value.
+func fromLPValue(x, inf float64) float64 {
+	switch {
+	case x >= inf:
+		return math.Inf(1)
+	case x <= -inf:
+		return math.Inf(-1)
+	default:
+		return x
+	}
+}
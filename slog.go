@@ -0,0 +1,140 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #cgo CFLAGS: -I/usr/include/lpsolve/
+// #cgo LDFLAGS: -llpsolve55 -lm -ldl -lcolamd
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Verbosity selects which messages the underlying solver logs. Each level
+// includes all messages of the levels before it.
+type Verbosity int
+
+const (
+	VerbosityNeutral   = Verbosity(C.NEUTRAL)
+	VerbosityCritical  = Verbosity(C.CRITICAL)
+	VerbositySevere    = Verbosity(C.SEVERE)
+	VerbosityImportant = Verbosity(C.IMPORTANT)
+	VerbosityNormal    = Verbosity(C.NORMAL)
+	VerbosityDetailed  = Verbosity(C.DETAILED)
+	VerbosityFull      = Verbosity(C.FULL)
+)
+
+// logLevel is the slog level of all messages of the underlying solver.
+const logLevel = slog.LevelInfo
+
+// Log phases, as attached to structured log records.
+const (
+	phaseModel = "model"
+	phaseSolve = "solve"
+)
+
+// String returns a string representation of the verbosity level.
+func (v Verbosity) String() string {
+	switch v {
+	case VerbosityNeutral:
+		return "neutral"
+	case VerbosityCritical:
+		return "critical"
+	case VerbositySevere:
+		return "severe"
+	case VerbosityImportant:
+		return "important"
+	case VerbosityNormal:
+		return "normal"
+	case VerbosityDetailed:
+		return "detailed"
+	case VerbosityFull:
+		return "full"
+	default:
+		return fmt.Sprintf("Verbosity(%d)", int(v))
+	}
+}
+
+func (v Verbosity) validate() error {
+	if v < VerbosityNeutral || v > VerbosityFull {
+		return fmt.Errorf("invalid verbosity: %d", int(v))
+	}
+
+	return nil
+}
+
+// Verbosity returns the model's current verbosity level.
+func (model *Model) Verbosity() Verbosity {
+	model.mu.RLock()
+	defer model.mu.RUnlock()
+
+	model.mustBeOpen()
+
+	return Verbosity(C.get_verbose(model.prob))
+}
+
+// SetVerbosity changes which messages are logged by the underlying solver.
+func (model *Model) SetVerbosity(v Verbosity) error {
+	if err := v.validate(); err != nil {
+		return err
+	}
+
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	if err := model.checkOpen(); err != nil {
+		return err
+	}
+
+	model.setVerbosity(v)
+
+	return nil
+}
+
+func (model *Model) setVerbosity(v Verbosity) {
+	C.set_verbose(model.prob, C.int(v))
+}
+
+// logRecord passes a message of the underlying solver to the slog handler.
+// The solver does not report the level of individual messages, so they are
+// all logged at logLevel; which messages are logged is controlled by the
+// problem's verbosity instead.
+func (sink *logSink) logRecord(prob *C.lprec, msg string) {
+	ctx := context.Background()
+	if !sink.slogHandler.Enabled(ctx, logLevel) {
+		return
+	}
+
+	verbosity := Verbosity(C.get_verbose(prob))
+
+	phase := phaseModel
+	if sink.solving {
+		phase = phaseSolve
+	}
+
+	slog.New(sink.slogHandler).LogAttrs(ctx, logLevel, strings.TrimSpace(msg),
+		slog.String("model", C.GoString(C.get_lp_name(prob))),
+		slog.String("phase", phase),
+		slog.String("verbosity", verbosity.String()),
+	)
+}
@@ -0,0 +1,265 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #cgo CFLAGS: -I/usr/include/lpsolve/
+// #cgo LDFLAGS: -llpsolve55 -lm -ldl -lcolamd
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+	"time"
+)
+
+// SolverOptions holds the parameters of the underlying solver.
+// The zero value is not usable; start from DefaultSolverOptions or from
+// Model.SolverOptions and change the desired fields.
+type SolverOptions struct {
+	// Timeout limits the duration of a solve. lp_solve only supports whole
+	// seconds, so the timeout is rounded up. Zero means no timeout.
+	Timeout time.Duration
+
+	// EpsilonInt is the tolerance used to decide whether a value is integer.
+	EpsilonInt float64
+	// EpsilonPivot is the value below which pivot elements are considered
+	// zero.
+	EpsilonPivot float64
+	// EpsilonElement is the value below which matrix elements are
+	// considered zero.
+	EpsilonElement float64
+	// EpsilonPrimal is the feasibility tolerance of the right-hand sides.
+	EpsilonPrimal float64
+	// EpsilonDual is the tolerance of the reduced costs.
+	EpsilonDual float64
+
+	// MIPGapAbsolute and MIPGapRelative are the gaps between the best
+	// solution found and the best bound at which branch-and-bound stops.
+	MIPGapAbsolute float64
+	MIPGapRelative float64
+
+	Scaling   ScalingMode
+	Pivoting  PivotingRule
+	Branching BranchMode
+
+	// DepthLimit is the maximum branch-and-bound depth. Negative values are
+	// relative to the number of integer variables; 0 means no limit.
+	DepthLimit int
+
+	Improve ImproveFlags
+
+	// Presolve selects the reductions applied to the model before solving.
+	// Like all models, presolved models are solved on a snapshot, so the
+	// model itself and its variables and constraints are not affected.
+	// Duals of presolved models are only computed if PresolveDuals or
+	// PresolveSensDuals is included.
+	Presolve PresolveFlags
+}
+
+// ScalingMode combines one scaling algorithm with any number of scaling
+// flags, e.g. ScaleGeometric | ScaleEquilibrate | ScaleIntegers.
+type ScalingMode int
+
+const (
+	ScaleNone       = ScalingMode(C.SCALE_NONE)
+	ScaleExtreme    = ScalingMode(C.SCALE_EXTREME)
+	ScaleRange      = ScalingMode(C.SCALE_RANGE)
+	ScaleMean       = ScalingMode(C.SCALE_MEAN)
+	ScaleGeometric  = ScalingMode(C.SCALE_GEOMETRIC)
+	ScaleCurtisReid = ScalingMode(C.SCALE_CURTISREID)
+
+	ScaleQuadratic   = ScalingMode(C.SCALE_QUADRATIC)
+	ScaleLogarithmic = ScalingMode(C.SCALE_LOGARITHMIC)
+	ScalePower2      = ScalingMode(C.SCALE_POWER2)
+	ScaleEquilibrate = ScalingMode(C.SCALE_EQUILIBRATE)
+	ScaleIntegers    = ScalingMode(C.SCALE_INTEGERS)
+	ScaleDynUpdate   = ScalingMode(C.SCALE_DYNUPDATE)
+)
+
+// PivotingRule combines one pricing rule with any number of pricing
+// flags, e.g. PivotDevex | PivotAdaptive.
+type PivotingRule int
+
+const (
+	PivotFirstIndex   = PivotingRule(C.PRICER_FIRSTINDEX)
+	PivotDantzig      = PivotingRule(C.PRICER_DANTZIG)
+	PivotDevex        = PivotingRule(C.PRICER_DEVEX)
+	PivotSteepestEdge = PivotingRule(C.PRICER_STEEPESTEDGE)
+
+	PivotPrimalFallback = PivotingRule(C.PRICE_PRIMALFALLBACK)
+	PivotMultiple       = PivotingRule(C.PRICE_MULTIPLE)
+	PivotPartial        = PivotingRule(C.PRICE_PARTIAL)
+	PivotAdaptive       = PivotingRule(C.PRICE_ADAPTIVE)
+	PivotRandomize      = PivotingRule(C.PRICE_RANDOMIZE)
+	PivotHarrisTwoPass  = PivotingRule(C.PRICE_HARRISTWOPASS)
+)
+
+// BranchMode selects which branch branch-and-bound explores first.
+type BranchMode int
+
+const (
+	BranchCeiling   = BranchMode(C.BRANCH_CEILING)
+	BranchFloor     = BranchMode(C.BRANCH_FLOOR)
+	BranchAutomatic = BranchMode(C.BRANCH_AUTOMATIC)
+)
+
+// ImproveFlags selects the iterative improvements performed while solving.
+type ImproveFlags int
+
+const (
+	ImproveSolution        = ImproveFlags(C.IMPROVE_SOLUTION)
+	ImproveDualFeasibility = ImproveFlags(C.IMPROVE_DUALFEAS)
+	ImproveThetaGap        = ImproveFlags(C.IMPROVE_THETAGAP)
+	ImproveBBSimplex       = ImproveFlags(C.IMPROVE_BBSIMPLEX)
+)
+
+// PresolveFlags selects the reductions performed by presolve, e.g.
+// PresolveRows | PresolveCols | PresolveLinDep.
+type PresolveFlags int
+
+const (
+	PresolveNone         = PresolveFlags(C.PRESOLVE_NONE)
+	PresolveRows         = PresolveFlags(C.PRESOLVE_ROWS)
+	PresolveCols         = PresolveFlags(C.PRESOLVE_COLS)
+	PresolveLinDep       = PresolveFlags(C.PRESOLVE_LINDEP)
+	PresolveSOS          = PresolveFlags(C.PRESOLVE_SOS)
+	PresolveReduceMIP    = PresolveFlags(C.PRESOLVE_REDUCEMIP)
+	PresolveKnapsack     = PresolveFlags(C.PRESOLVE_KNAPSACK)
+	PresolveElimEq2      = PresolveFlags(C.PRESOLVE_ELIMEQ2)
+	PresolveImpliedFree  = PresolveFlags(C.PRESOLVE_IMPLIEDFREE)
+	PresolveReduceGCD    = PresolveFlags(C.PRESOLVE_REDUCEGCD)
+	PresolveProbeFix     = PresolveFlags(C.PRESOLVE_PROBEFIX)
+	PresolveProbeReduce  = PresolveFlags(C.PRESOLVE_PROBEREDUCE)
+	PresolveRowDominate  = PresolveFlags(C.PRESOLVE_ROWDOMINATE)
+	PresolveColDominate  = PresolveFlags(C.PRESOLVE_COLDOMINATE)
+	PresolveMergeRows    = PresolveFlags(C.PRESOLVE_MERGEROWS)
+	PresolveImpliedSlack = PresolveFlags(C.PRESOLVE_IMPLIEDSLK)
+	PresolveColFixDual   = PresolveFlags(C.PRESOLVE_COLFIXDUAL)
+	PresolveBounds       = PresolveFlags(C.PRESOLVE_BOUNDS)
+	PresolveDuals        = PresolveFlags(C.PRESOLVE_DUALS)
+	PresolveSensDuals    = PresolveFlags(C.PRESOLVE_SENSDUALS)
+)
+
+// DefaultSolverOptions returns the default parameters of the underlying
+// solver.
+func DefaultSolverOptions() SolverOptions {
+	prob := C.make_lp(0, 0)
+	defer C.delete_lp(prob)
+
+	return getSolverOptions(prob)
+}
+
+// SolverOptions returns the solver parameters currently set for the model.
+func (model *Model) SolverOptions() SolverOptions {
+	model.mu.RLock()
+	defer model.mu.RUnlock()
+
+	model.mustBeOpen()
+
+	return model.solverOptions()
+}
+
+// SetSolverOptions changes the solver parameters of the model.
+func (model *Model) SetSolverOptions(opts SolverOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	if err := model.checkOpen(); err != nil {
+		return err
+	}
+
+	model.setSolverOptions(opts)
+
+	return nil
+}
+
+func (model *Model) solverOptions() SolverOptions {
+	return getSolverOptions(model.prob)
+}
+
+func (model *Model) setSolverOptions(opts SolverOptions) {
+	setSolverOptions(model.prob, opts)
+}
+
+func (opts SolverOptions) validate() error {
+	if opts.Timeout < 0 {
+		return fmt.Errorf("negative timeout: %s", opts.Timeout)
+	}
+	for name, eps := range map[string]float64{
+		"EpsilonInt":     opts.EpsilonInt,
+		"EpsilonPivot":   opts.EpsilonPivot,
+		"EpsilonElement": opts.EpsilonElement,
+		"EpsilonPrimal":  opts.EpsilonPrimal,
+		"EpsilonDual":    opts.EpsilonDual,
+	} {
+		if eps <= 0 {
+			return fmt.Errorf("%s must be positive: %g", name, eps)
+		}
+	}
+	if opts.MIPGapAbsolute < 0 || opts.MIPGapRelative < 0 {
+		return fmt.Errorf("negative MIP gap: %g/%g", opts.MIPGapAbsolute, opts.MIPGapRelative)
+	}
+
+	return nil
+}
+
+func getSolverOptions(prob *C.lprec) SolverOptions {
+	return SolverOptions{
+		Timeout:        time.Duration(C.get_timeout(prob)) * time.Second,
+		EpsilonInt:     float64(C.get_epsint(prob)),
+		EpsilonPivot:   float64(C.get_epspivot(prob)),
+		EpsilonElement: float64(C.get_epsel(prob)),
+		EpsilonPrimal:  float64(C.get_epsb(prob)),
+		EpsilonDual:    float64(C.get_epsd(prob)),
+		MIPGapAbsolute: float64(C.get_mip_gap(prob, C.TRUE)),
+		MIPGapRelative: float64(C.get_mip_gap(prob, C.FALSE)),
+		Scaling:        ScalingMode(C.get_scaling(prob)),
+		Pivoting:       PivotingRule(C.get_pivoting(prob)),
+		Branching:      BranchMode(C.get_floorfirst(prob)),
+		DepthLimit:     int(C.get_bb_depthlimit(prob)),
+		Improve:        ImproveFlags(C.get_improve(prob)),
+		Presolve:       PresolveFlags(C.get_presolve(prob)),
+	}
+}
+
+func setSolverOptions(prob *C.lprec, opts SolverOptions) {
+	timeout := opts.Timeout / time.Second
+	if opts.Timeout%time.Second != 0 {
+		timeout++
+	}
+
+	C.set_timeout(prob, C.long(timeout))
+	C.set_epsint(prob, C.REAL(opts.EpsilonInt))
+	C.set_epspivot(prob, C.REAL(opts.EpsilonPivot))
+	C.set_epsel(prob, C.REAL(opts.EpsilonElement))
+	C.set_epsb(prob, C.REAL(opts.EpsilonPrimal))
+	C.set_epsd(prob, C.REAL(opts.EpsilonDual))
+	C.set_mip_gap(prob, C.TRUE, C.REAL(opts.MIPGapAbsolute))
+	C.set_mip_gap(prob, C.FALSE, C.REAL(opts.MIPGapRelative))
+	C.set_scaling(prob, C.int(opts.Scaling))
+	C.set_pivoting(prob, C.int(opts.Pivoting))
+	C.set_floorfirst(prob, C.int(opts.Branching))
+	C.set_bb_depthlimit(prob, C.int(opts.DepthLimit))
+	C.set_improve(prob, C.int(opts.Improve))
+	C.set_presolve(prob, C.int(opts.Presolve), C.get_presolveloops(prob))
+}
@@ -0,0 +1,101 @@
+/*
+Copyright © 2015-2022 Leo Antunes <leo@costela.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package golpa
+
+// #cgo CFLAGS: -I/usr/include/lpsolve/
+// #cgo LDFLAGS: -llpsolve55 -lm -ldl -lcolamd
+// #include <lp_lib.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+	"math"
+	"unsafe"
+)
+
+// SOSType is the type of a special ordered set.
+type SOSType int
+
+const (
+	// SOS1 allows at most one variable of the set to be non-zero.
+	SOS1 = SOSType(C.SOS1)
+	// SOS2 allows at most two variables of the set to be non-zero, which
+	// must be adjacent in the order given by the weights.
+	SOS2 = SOSType(C.SOS2)
+)
+
+// AddSOS adds a special ordered set of the given type over the given
+// variables. The weights order the variables and must be distinct; if nil,
+// the variables are ordered as given. Sets with lower priority values are
+// branched on first.
+func (model *Model) AddSOS(sosType SOSType, priority int, vars []*Variable, weights []float64) error {
+	if sosType != SOS1 && sosType != SOS2 {
+		return fmt.Errorf("unknown SOS type: %d", sosType)
+	}
+	if len(vars) == 0 {
+		return fmt.Errorf("empty SOS")
+	}
+	if weights == nil {
+		weights = make([]float64, len(vars))
+		for i := range weights {
+			weights[i] = float64(i + 1)
+		}
+	}
+	if len(vars) != len(weights) {
+		return fmt.Errorf("inconsistent number of variables and weights: %d != %d", len(vars), len(weights))
+	}
+	seen := make(map[float64]bool, len(weights))
+	for i, w := range weights {
+		if math.IsNaN(w) {
+			return fmt.Errorf("weight %d is NaN", i)
+		}
+		if seen[w] {
+			return fmt.Errorf("duplicate weight %g", w)
+		}
+		seen[w] = true
+	}
+
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	if err := model.checkOpen(); err != nil {
+		return err
+	}
+
+	colno := make([]C.int, len(vars))
+	row := make([]C.REAL, len(vars))
+	for i, v := range vars {
+		if v.model != model || v.index < 0 {
+			return fmt.Errorf("variable %d not part of model", i)
+		}
+		colno[i] = C.int(v.index + 1)
+		row[i] = C.REAL(weights[i])
+	}
+
+	model.sosCount++
+
+	c_name := C.CString(fmt.Sprintf("SOS%d", model.sosCount))
+	defer C.free(unsafe.Pointer(c_name))
+
+	if C.add_SOS(model.prob, c_name, C.int(sosType), C.int(priority), C.int(len(vars)), &colno[0], &row[0]) == 0 {
+		return fmt.Errorf("could not add SOS")
+	}
+
+	return nil
+}
@@ -24,12 +24,16 @@ package golpa
 import "C"
 
 import (
+	"fmt"
 	"math"
 )
 
 type Variable struct {
 	model *Model
 	index int
+	// unfixed holds the bounds from before Fix was called, if the variable
+	// is fixed
+	unfixed *Bounds
 }
 
 type VariableType int
@@ -38,6 +42,12 @@ const (
 	ContinuousVariable VariableType = iota
 	IntegerVariable
 	BinaryVariable
+	// SemiContinuousVariable is either zero or between its lower and upper
+	// bounds.
+	SemiContinuousVariable
+	// SemiContinuousIntegerVariable is an integer that is either zero or
+	// between its lower and upper bounds.
+	SemiContinuousIntegerVariable
 )
 
 /* variable-related functions (model variables, as opposed to Go variables) */
@@ -47,6 +57,8 @@ func (v *Variable) Name() string {
 	v.model.mu.RLock()
 	defer v.model.mu.RUnlock()
 
+	v.model.mustBeOpen()
+
 	return C.GoString(C.get_col_name(v.model.prob, C.int(v.index+1)))
 }
 
@@ -54,17 +66,32 @@ func (v *Variable) Name() string {
 //    - ContinuousVariable
 //    - Integervariable
 //    - BinaryVariable
+//    - SemiContinuousVariable
+//    - SemiContinuousIntegerVariable
 func (v *Variable) SetType(vartype VariableType) {
 	v.model.mu.Lock()
 	defer v.model.mu.Unlock()
 
+	v.model.mustBeOpen()
+
+	col := C.int(v.index + 1)
+
 	switch vartype {
 	case ContinuousVariable:
-		C.set_int(v.model.prob, C.int(v.index+1), C.FALSE)
+		C.set_int(v.model.prob, col, C.FALSE)
+		C.set_semicont(v.model.prob, col, C.FALSE)
 	case IntegerVariable:
-		C.set_int(v.model.prob, C.int(v.index+1), C.TRUE)
+		C.set_int(v.model.prob, col, C.TRUE)
+		C.set_semicont(v.model.prob, col, C.FALSE)
 	case BinaryVariable:
-		C.set_binary(v.model.prob, C.int(v.index+1), C.TRUE)
+		C.set_semicont(v.model.prob, col, C.FALSE)
+		C.set_binary(v.model.prob, col, C.TRUE)
+	case SemiContinuousVariable:
+		C.set_int(v.model.prob, col, C.FALSE)
+		C.set_semicont(v.model.prob, col, C.TRUE)
+	case SemiContinuousIntegerVariable:
+		C.set_int(v.model.prob, col, C.TRUE)
+		C.set_semicont(v.model.prob, col, C.TRUE)
 	default:
 		panic("unrecognized variable type!")
 	}
@@ -75,63 +102,209 @@ func (v *Variable) Type() VariableType {
 	v.model.mu.RLock()
 	defer v.model.mu.RUnlock()
 
+	v.model.mustBeOpen()
+
+	semicont := C.is_semicont(v.model.prob, C.int(v.index+1)) == C.TRUE
+
 	if C.is_binary(v.model.prob, C.int(v.index+1)) == C.TRUE {
 		return BinaryVariable
 	} else if C.is_int(v.model.prob, C.int(v.index+1)) == C.TRUE {
+		if semicont {
+			return SemiContinuousIntegerVariable
+		}
 		return IntegerVariable
+	} else if semicont {
+		return SemiContinuousVariable
 	} else {
 		return ContinuousVariable
 	}
 }
 
 // SetBounds sets the boundaries for the given variable.
-// To set a bound to infinity, pass math.Inf(1) or math.Inf(-1). The
-// signal of the infinity is ignored, as the lower and upper bounds are
-// always assumed to be the negative and positive infinities,
-// respectively.
-func (v *Variable) SetBounds(lower, upper float64) {
+// To remove a bound, pass math.Inf(-1) as lower or math.Inf(1) as upper
+// bound. An error is returned if the lower bound is larger than the upper
+// bound, if a bound is NaN or if a bound is an infinity of the wrong sign.
+func (v *Variable) SetBounds(lower, upper float64) error {
+	if err := checkBounds(lower, upper); err != nil {
+		return err
+	}
+
+	v.model.mu.Lock()
+	defer v.model.mu.Unlock()
+
+	if err := v.model.checkOpen(); err != nil {
+		return err
+	}
+
+	v.unfixed = nil
+	setColBounds(v.model.prob, v.index+1, lower, upper)
+
+	return nil
+}
+
+// SetLowerBound sets the lower bound of the variable, keeping its upper
+// bound. Pass math.Inf(-1) to remove the lower bound.
+func (v *Variable) SetLowerBound(lower float64) error {
+	v.model.mu.Lock()
+	defer v.model.mu.Unlock()
+
+	if err := v.model.checkOpen(); err != nil {
+		return err
+	}
+
+	_, upper := colBounds(v.model.prob, v.index+1)
+	if err := checkBounds(lower, upper); err != nil {
+		return err
+	}
+
+	v.unfixed = nil
+	setColBounds(v.model.prob, v.index+1, lower, upper)
+
+	return nil
+}
+
+// SetUpperBound sets the upper bound of the variable, keeping its lower
+// bound. Pass math.Inf(1) to remove the upper bound.
+func (v *Variable) SetUpperBound(upper float64) error {
+	v.model.mu.Lock()
+	defer v.model.mu.Unlock()
+
+	if err := v.model.checkOpen(); err != nil {
+		return err
+	}
+
+	lower, _ := colBounds(v.model.prob, v.index+1)
+	if err := checkBounds(lower, upper); err != nil {
+		return err
+	}
+
+	v.unfixed = nil
+	setColBounds(v.model.prob, v.index+1, lower, upper)
+
+	return nil
+}
+
+// SetFree removes both bounds of the variable. Note that variables created
+// directly through the underlying library default to a lower bound of 0.
+func (v *Variable) SetFree() error {
+	return v.SetBounds(math.Inf(-1), math.Inf(1))
+}
+
+// Fix sets both bounds of the variable to the given value. The previous
+// bounds are restored by Unfix.
+func (v *Variable) Fix(value float64) error {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return fmt.Errorf("invalid value to fix variable to: %f", value)
+	}
+
 	v.model.mu.Lock()
 	defer v.model.mu.Unlock()
 
+	if err := v.model.checkOpen(); err != nil {
+		return err
+	}
+
+	// fixing a fixed variable keeps the bounds from before the first Fix
+	if v.unfixed == nil {
+		lower, upper := colBounds(v.model.prob, v.index+1)
+		v.unfixed = &Bounds{Lower: lower, Upper: upper}
+	}
+
+	setColBounds(v.model.prob, v.index+1, value, value)
+
+	return nil
+}
+
+// Unfix restores the bounds the variable had before Fix was called. An
+// error is returned if the variable is not fixed, including when its
+// bounds were changed after fixing it.
+func (v *Variable) Unfix() error {
+	v.model.mu.Lock()
+	defer v.model.mu.Unlock()
+
+	if err := v.model.checkOpen(); err != nil {
+		return err
+	}
+
+	if v.unfixed == nil {
+		return fmt.Errorf("variable not fixed")
+	}
+
+	setColBounds(v.model.prob, v.index+1, v.unfixed.Lower, v.unfixed.Upper)
+	v.unfixed = nil
+
+	return nil
+}
+
+// checkBounds returns an error if the given bounds are not valid for a
+// variable or constraint.
+func checkBounds(lower, upper float64) error {
 	switch {
-	case math.IsInf(lower, 0) && math.IsInf(upper, 0):
-		C.set_unbounded(v.model.prob, C.int(v.index+1))
-	case math.IsInf(lower, 0):
-		C.set_unbounded(v.model.prob, C.int(v.index+1))
-		C.set_upbo(v.model.prob, C.int(v.index+1), C.double(upper))
-	case math.IsInf(upper, 0):
-		C.set_unbounded(v.model.prob, C.int(v.index+1))
-		C.set_lowbo(v.model.prob, C.int(v.index+1), C.double(lower))
-	default:
-		C.set_bounds(v.model.prob, C.int(v.index+1), C.double(lower), C.double(upper))
+	case math.IsNaN(lower) || math.IsNaN(upper):
+		return fmt.Errorf("NaN bound: %f, %f", lower, upper)
+	case math.IsInf(lower, 1):
+		return fmt.Errorf("lower bound is positive infinity")
+	case math.IsInf(upper, -1):
+		return fmt.Errorf("upper bound is negative infinity")
+	case lower > upper:
+		return fmt.Errorf("lower bound larger than upper bound: %f > %f", lower, upper)
 	}
+
+	return nil
 }
 
-// Bounds returns the bounds currently set for this variable.
-func (v *Variable) Bounds() (lower, upper float64) {
-	v.model.mu.RLock()
-	defer v.model.mu.RUnlock()
+// setColBounds sets the bounds of the given column, which must have been
+// validated with checkBounds.
+func setColBounds(prob *C.lprec, colnr int, lower, upper float64) {
+	inf := C.get_infinite(prob)
+
+	lo, up := C.REAL(lower), C.REAL(upper)
+	if math.IsInf(lower, -1) {
+		lo = -inf
+	}
+	if math.IsInf(upper, 1) {
+		up = inf
+	}
 
-	lower = float64(C.get_lowbo(v.model.prob, C.int(v.index+1)))
-	upper = float64(C.get_upbo(v.model.prob, C.int(v.index+1)))
+	// both bounds are set at once, since setting them one by one might
+	// temporarily make them inconsistent
+	C.set_bounds(prob, C.int(colnr), lo, up)
+}
 
-	inf := float64(C.get_infinite(v.model.prob))
+// colBounds returns the bounds of the given column.
+func colBounds(prob *C.lprec, colnr int) (lower, upper float64) {
+	lower = float64(C.get_lowbo(prob, C.int(colnr)))
+	upper = float64(C.get_upbo(prob, C.int(colnr)))
 
-	if lower == -inf {
+	inf := float64(C.get_infinite(prob))
+
+	if lower <= -inf {
 		lower = math.Inf(-1)
 	}
-	if upper == inf {
+	if upper >= inf {
 		upper = math.Inf(1)
 	}
 	return
 }
 
+// Bounds returns the bounds currently set for this variable.
+func (v *Variable) Bounds() (lower, upper float64) {
+	v.model.mu.RLock()
+	defer v.model.mu.RUnlock()
+
+	v.model.mustBeOpen()
+
+	return colBounds(v.model.prob, v.index+1)
+}
+
 // SetObjectiveCoefficient sets the coefficient for this variable in
 // the objective function.
 func (v *Variable) SetObjectiveCoefficient(coef float64) {
 	v.model.mu.Lock()
 	defer v.model.mu.Unlock()
 
+	v.model.mustBeOpen()
+
 	C.set_mat(v.model.prob, C.int(0), C.int(v.index+1), C.REAL(coef))
 }
 
@@ -141,5 +314,7 @@ func (v *Variable) Coefficient() float64 {
 	v.model.mu.RLock()
 	defer v.model.mu.RUnlock()
 
+	v.model.mustBeOpen()
+
 	return float64(C.get_mat(v.model.prob, C.int(0), C.int(v.index+1)))
 }